@@ -0,0 +1,115 @@
+package sudoku
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The symbols standing for the values of a board, the first for 1, ie.
+// "WORDPLAYS" for a wordoku whose values are the letters of a word. The
+// symbols are ASCII letters, each appearing once regardless of case, and are
+// read in either case.
+type Alphabet string
+
+// Returns an error if the symbols of the alphabet are not letters or repeat.
+func (a Alphabet) checkSymbols() error {
+	seen := map[byte]bool{}
+	for i := 0; i < len(a); i++ {
+		c := a[i] | 0x20
+		if c < 'a' || c > 'z' {
+			error := fmt.Sprintf("Alphabet symbol %q is not a letter", a[i])
+			return errors.New(error)
+		}
+		if seen[c] {
+			error := fmt.Sprintf("Alphabet has symbol %q twice", a[i])
+			return errors.New(error)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// Returns an error if the alphabet does not have a symbol per value of the
+// board.
+func (a Alphabet) check(b Board) error {
+	if err := a.checkSymbols(); err != nil {
+		return err
+	}
+	if size := boardSize(len(b)); len(a) != size {
+		error := fmt.Sprintf("Alphabet has %d symbols, expected %d", len(a), size)
+		return errors.New(error)
+	}
+	return nil
+}
+
+// Replaces the symbols of the alphabet in the text formats of data with the
+// characters of their values, so Parse reads it. Links, .sdk metadata and
+// json objects are left as they are, the values of json arrays being
+// numbers.
+func (a Alphabet) Decode(data []byte) ([]byte, error) {
+	if err := a.checkSymbols(); err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("{")) || bytes.HasPrefix(trimmed, []byte("http://")) ||
+		bytes.HasPrefix(trimmed, []byte("https://")) {
+		return data, nil
+	}
+
+	var values [256]byte
+	for i := 0; i < len(a); i++ {
+		values[a[i]|0x20] = valueChar(i + 1)
+		values[a[i]&^0x20] = valueChar(i + 1)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	result := make([]byte, 0, len(data))
+	for _, line := range lines {
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 && (trimmed[0] == '#' || trimmed[0] == '[') {
+			result = append(result, line...)
+			continue
+		}
+		for _, c := range line {
+			if values[c] != 0 {
+				c = values[c]
+			}
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// Parses a board like Parse, with the symbols of the alphabet in place of
+// the values.
+func (a Alphabet) Parse(data []byte) (Board, error) {
+	data, err := a.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	board, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.check(board); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// Replaces the values in text, the board written in a text format where
+// every character of a value is one (ie. Line, String, SS or CSV), with the
+// symbols of the alphabet.
+func (a Alphabet) Format(b Board, text string) (string, error) {
+	if err := a.check(b); err != nil {
+		return "", err
+	}
+	return strings.Map(func(r rune) rune {
+		for i := 0; i < len(a); i++ {
+			if r == rune(valueChar(i+1)) {
+				return rune(a[i])
+			}
+		}
+		return r
+	}, text), nil
+}
@@ -0,0 +1,336 @@
+package sudoku
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Returns the canonical form of the board, the same for every board it can
+// be transformed to (see Transform): the smallest of them read row by row,
+// empty cells being smaller than values (minlex). Takes milliseconds for
+// 9x9 boards, boards with boxes larger than 3x3, ie. 12x12 and up, are
+// ErrBadSize as the search for theirs is too slow.
+func (b Board) Canonical() (Board, error) {
+	canonical, _, err := b.canonical(context.Background())
+	return canonical, err
+}
+
+// Returns a hash of the canonical form of the board as hex, the same for
+// boards that are transforms of each other and different for others.
+func (b Board) Fingerprint() (string, error) {
+	canonical, err := b.Canonical()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(canonical.Line()))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Returns the transform making the canonical form of the board, ie. to map
+// the solution of the canonical form back to the board with its Inverse.
+func (b Board) CanonicalTransform() (Transform, error) {
+	return b.CanonicalTransformContext(context.Background())
+}
+
+// Like CanonicalTransform, but gives up and returns the context's error as
+// soon as ctx is cancelled or its deadline is exceeded.
+func (b Board) CanonicalTransformContext(ctx context.Context) (Transform, error) {
+	_, t, err := b.canonical(ctx)
+	return t, err
+}
+
+// Returns an error if the canonical form of the board is not found: ErrBadSize
+// if it is not a supported size, or has boxes larger than 3x3.
+func (b Board) CheckCanonical() error {
+	s, err := b.shape()
+	if err != nil {
+		return err
+	}
+	return s.checkCanonical()
+}
+
+// Returns true if board a can be transformed to board b (see Transform), and
+// the transform doing it, or an error if either board is not valid or is a
+// size whose canonical form is not found (see CheckCanonical).
+func Equivalent(a Board, b Board) (Transform, bool, error) {
+	for _, board := range []Board{a, b} {
+		if err := board.CheckCanonical(); err != nil {
+			return Transform{}, false, err
+		}
+	}
+	if len(a) != len(b) {
+		return Transform{}, false, nil
+	}
+	canonicalA, toCanonical, err := a.canonical(context.Background())
+	if err != nil {
+		return Transform{}, false, err
+	}
+	canonicalB, fromCanonical, err := b.canonical(context.Background())
+	if err != nil {
+		return Transform{}, false, err
+	}
+	if compareValues(canonicalA, canonicalB) != 0 {
+		return Transform{}, false, nil
+	}
+	return toCanonical.Then(fromCanonical.Inverse()), true, nil
+}
+
+// Returns the canonical form of the board, and the transform making it, or
+// the context's error if ctx is done first.
+func (b Board) canonical(ctx context.Context) (Board, Transform, error) {
+	s, err := b.shape()
+	if err != nil {
+		return nil, Transform{}, err
+	}
+	if err := b.checkRange(s); err != nil {
+		return nil, Transform{}, err
+	}
+	if err := s.checkCanonical(); err != nil {
+		return nil, Transform{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, Transform{}, err
+	}
+
+	m := &minlex{ctx: ctx, s: s, rows: make([]int, s.size), used: make([]bool, s.size), current: make(Board, len(b))}
+	transposed := make(Board, len(b))
+	for y := 0; y < s.size; y++ {
+		for x := 0; x < s.size; x++ {
+			transposed[s.cell(x, y)] = b[s.cell(y, x)]
+		}
+	}
+	for _, transpose := range []bool{false, true} {
+		if transpose && s.boxRows != s.boxColumns {
+			break
+		}
+		m.grid, m.transpose = b, transpose
+		if transpose {
+			m.grid = transposed
+		}
+		for _, columns := range lineOrders(s.size, s.boxColumns) {
+			m.columns = columns
+			m.search(0, [maxSize + 1]int{}, 1)
+			if m.err != nil {
+				return nil, Transform{}, m.err
+			}
+		}
+	}
+	return m.best, m.transform, nil
+}
+
+// The largest box rows or columns the canonical form is found for: the
+// orders of the lines searched grow with the factorial of both.
+const maxCanonicalBox = 3
+
+// Returns ErrBadSize if the canonical form of boards of the shape is not
+// found, as their boxes are too large.
+func (s *shape) checkCanonical() error {
+	if s.boxRows > maxCanonicalBox || s.boxColumns > maxCanonicalBox {
+		return fmt.Errorf("%w: canonical forms are only found for boxes up to %dx%d, not %dx%d",
+			ErrBadSize, maxCanonicalBox, maxCanonicalBox, s.boxRows, s.boxColumns)
+	}
+	return nil
+}
+
+// The search for the canonical form of a board, which picks the order of
+// the rows for each order of the columns, keeping those making the smallest
+// rows so far.
+type minlex struct {
+	ctx context.Context
+	s   *shape
+	// The board, transposed if transpose is set, and the order of its
+	// columns.
+	grid      Board
+	transpose bool
+	columns   []int
+	// The rows picked, and the board they make.
+	rows    []int
+	used    []bool
+	current Board
+	// The smallest board found, and the transform making it.
+	best      Board
+	transform Transform
+	// The searches so far, the context is checked every few of them, and
+	// its error once it is done.
+	calls int
+	err   error
+}
+
+// Picks row level onwards, with labels the values given to the values seen
+// in the rows picked and next the next value to give.
+func (m *minlex) search(level int, labels [maxSize + 1]int, next int) {
+	if m.calls++; m.calls%1024 == 0 && m.err == nil {
+		m.err = m.ctx.Err()
+	}
+	if m.err != nil {
+		return
+	}
+	size := m.s.size
+	if level == size {
+		if m.best == nil || compareValues(m.current, m.best) < 0 {
+			m.best = append(Board(nil), m.current...)
+			m.record(labels, next)
+		}
+		return
+	}
+
+	// Keep the rows making the smallest row, with the labels they give.
+	type option struct {
+		row    int
+		values []int
+		labels [maxSize + 1]int
+		next   int
+	}
+	options := []option{}
+	// Empty rows of the same band make the same boards, keep one of them.
+	emptyBands := map[int]bool{}
+	for _, row := range m.candidates(level) {
+		o := option{row, make([]int, size), labels, next}
+		for j, column := range m.columns {
+			val := m.grid[m.s.cell(row, column)]
+			if val != 0 && o.labels[val] == 0 {
+				o.labels[val] = o.next
+				o.next++
+			}
+			o.values[j] = o.labels[val]
+		}
+		if band := row / m.s.boxRows; isEmpty(o.values) {
+			if emptyBands[band] {
+				continue
+			}
+			emptyBands[band] = true
+		}
+		if len(options) > 0 {
+			if c := compareValues(o.values, options[0].values); c > 0 {
+				continue
+			} else if c < 0 {
+				options = options[:0]
+			}
+		}
+		options = append(options, o)
+	}
+
+	end := (level + 1) * size
+	for _, o := range options {
+		copy(m.current[level*size:], o.values)
+		if m.best != nil && compareValues(m.current[:end], m.best[:end]) > 0 {
+			return
+		}
+		m.rows[level] = o.row
+		m.used[o.row] = true
+		m.search(level+1, o.labels, o.next)
+		m.used[o.row] = false
+	}
+}
+
+// Returns the rows that can be picked for row level: any row of a band not
+// used yet when starting a band, or the rows left in the band being picked.
+func (m *minlex) candidates(level int) []int {
+	band := m.s.boxRows
+	result := []int{}
+	for row := 0; row < m.s.size; row++ {
+		first := row - row%band
+		if level%band != 0 && first != m.rows[level-1]-m.rows[level-1]%band {
+			continue
+		}
+		if m.used[row] || level%band == 0 && containsTrue(m.used[first:first+band]) {
+			continue
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+// Records the transform making the current board, with labels the values
+// given to the values seen.
+func (m *minlex) record(labels [maxSize + 1]int, next int) {
+	t := Identity(m.s.size)
+	t.Transpose = m.transpose
+	copy(t.Rows, m.rows)
+	copy(t.Columns, m.columns)
+	for v := 1; v <= m.s.size; v++ {
+		// Values not on the board keep their order after the others.
+		if labels[v] == 0 {
+			labels[v] = next
+			next++
+		}
+		t.Values[v] = labels[v]
+	}
+	m.transform = t
+}
+
+// Returns every order of size lines, ie. columns, keeping the lines of each
+// group of groupSize lines, ie. a stack, together.
+func lineOrders(size int, groupSize int) [][]int {
+	result := [][]int{}
+	inGroup := permutations(groupSize)
+	for _, groups := range permutations(size / groupSize) {
+		orders := [][]int{{}}
+		for _, group := range groups {
+			longer := [][]int{}
+			for _, order := range orders {
+				for _, p := range inGroup {
+					next := append([]int(nil), order...)
+					for _, i := range p {
+						next = append(next, group*groupSize+i)
+					}
+					longer = append(longer, next)
+				}
+			}
+			orders = longer
+		}
+		result = append(result, orders...)
+	}
+	return result
+}
+
+// Returns every order of the numbers from 0 to n-1.
+func permutations(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	result := [][]int{}
+	for _, p := range permutations(n - 1) {
+		for i := 0; i <= len(p); i++ {
+			next := append(append(append([]int(nil), p[:i]...), n-1), p[i:]...)
+			result = append(result, next)
+		}
+	}
+	return result
+}
+
+// Returns true if every value of s is 0.
+func isEmpty(s []int) bool {
+	for _, v := range s {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if any of s is true.
+func containsTrue(s []bool) bool {
+	for _, v := range s {
+		if v {
+			return true
+		}
+	}
+	return false
+}
+
+// Compares a and b, which have the same length, value by value: -1 if a is
+// smaller, 1 if it is larger, 0 if they are equal.
+func compareValues(a []int, b []int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
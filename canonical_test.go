@@ -0,0 +1,122 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+const easyLine = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+
+func TestCanonical(t *testing.T) {
+	board, err := ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := board.Canonical()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transforms := map[string]Transform{
+		"identity":     Identity(9),
+		"rotate":       Identity(9).Rotate(),
+		"transpose":    Identity(9).Transposed(),
+		"mirror":       Identity(9).MirrorColumns().MirrorRows(),
+		"swap rows":    Identity(9).SwapRows(3, 5),
+		"swap columns": Identity(9).SwapColumns(0, 2),
+		"swap bands":   Identity(9).SwapBands(3, 0, 2),
+		"swap stacks":  Identity(9).SwapStacks(3, 1, 2),
+		"relabel":      Identity(9).Relabel([]int{9, 8, 7, 6, 5, 4, 3, 2, 1}),
+		"all":          Identity(9).Rotate().SwapBands(3, 0, 1).SwapRows(0, 2).Relabel([]int{2, 1, 3, 4, 5, 6, 7, 8, 9}),
+	}
+	for name, transform := range transforms {
+		t.Run(name, func(t *testing.T) {
+			transformed, err := transform.Apply(board)
+			if err != nil {
+				t.Fatal(err)
+			}
+			canonical, err := transformed.Canonical()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if canonical.Line() != want.Line() {
+				t.Errorf("got canonical form %s, want %s", canonical.Line(), want.Line())
+			}
+			toCanonical, err := transformed.CanonicalTransform()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if applied, err := toCanonical.Apply(transformed); err != nil || applied.Line() != want.Line() {
+				t.Errorf("got %s, %v applying the canonical transform, want %s", applied.Line(), err, want.Line())
+			}
+		})
+	}
+}
+
+func TestCanonicalSizes(t *testing.T) {
+	tests := []struct {
+		size int
+		err  error
+	}{
+		{4, nil},
+		{6, nil},
+		{9, nil},
+		{12, ErrBadSize},
+		{16, ErrBadSize},
+		{25, ErrBadSize},
+	}
+	for _, test := range tests {
+		if _, err := make(Board, test.size*test.size).Canonical(); !errors.Is(err, test.err) {
+			t.Errorf("got %v for a %dx%d board, want %v", err, test.size, test.size, test.err)
+		}
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	board, err := ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := Identity(9).Rotate().Relabel([]int{2, 1, 3, 4, 5, 6, 7, 8, 9}).Apply(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := append(Board(nil), board...)
+	other[0] = 1
+	tests := []struct {
+		name  string
+		a, b  Board
+		equal bool
+		err   error
+	}{
+		{"same", board, board, true, nil},
+		{"transformed", board, rotated, true, nil},
+		{"different", board, other, false, nil},
+		{"different sizes", board, make(Board, 16), false, nil},
+		{"too large", board, make(Board, 256), false, ErrBadSize},
+		{"both too large", make(Board, 144), make(Board, 144), false, ErrBadSize},
+		{"not a size", board, make(Board, 80), false, ErrBadSize},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transform, equal, err := Equivalent(test.a, test.b)
+			if !errors.Is(err, test.err) || equal != test.equal {
+				t.Fatalf("got %t, %v, want %t, %v", equal, err, test.equal, test.err)
+			}
+			if !equal {
+				return
+			}
+			if transformed, err := transform.Apply(test.a); err != nil || transformed.Line() != test.b.Line() {
+				t.Errorf("got %s, %v applying the transform, want %s", transformed.Line(), err, test.b.Line())
+			}
+		})
+	}
+}
+
+func TestCanonicalTransformContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := make(Board, 81).CanonicalTransformContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,119 @@
+/* This package exports the solver as a C library:
+ *
+ *   go build -buildmode=c-shared -o libsudoku.so ./cmd/libsudoku
+ *
+ * which also writes libsudoku.h declaring these functions:
+ *
+ *   int SudokuSolve(char *board, char *solution)
+ *       solves the 9x9 board, in any format sudoku.Parse understands, ie. an
+ *       81 character line, and writes the solution as a line to solution.
+ *       Other sizes are SUDOKU_INVALID, as they don't fit the buffer.
+ *   int SudokuGenerate(long long seed, int difficulty, char *puzzle)
+ *       writes a puzzle for the seed as a line to puzzle. The difficulty is
+ *       1 (easy) to 5 (extreme), or 0 for any.
+ *   int SudokuRate(char *board, int *score, int *difficulty)
+ *       rates the puzzle, see sudoku.Rating.
+ *
+ * Lines are 81 characters written with a terminating NUL, so the buffers must
+ * hold at least SUDOKU_LINE_SIZE bytes. Every function returns SUDOKU_OK on success, or
+ * one of the other SUDOKU_ codes below.
+ */
+package main
+
+/*
+// The size of a buffer holding a board as a line.
+#define SUDOKU_LINE_SIZE 82
+
+// The result codes, the same as the exit codes of the sudoku command.
+enum {
+	SUDOKU_OK = 0,
+	SUDOKU_INVALID = 2,
+	SUDOKU_UNSOLVABLE = 3,
+	SUDOKU_NOT_UNIQUE = 6
+};
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+func main() {}
+
+// Returns the result code for an error from the sudoku package.
+func code(err error) C.int {
+	switch {
+	case err == nil:
+		return C.SUDOKU_OK
+	case errors.Is(err, sudoku.ErrUnsolvable):
+		return C.SUDOKU_UNSOLVABLE
+	case errors.Is(err, sudoku.ErrNotUnique):
+		return C.SUDOKU_NOT_UNIQUE
+	default:
+		return C.SUDOKU_INVALID
+	}
+}
+
+// Writes the board as a NUL terminated line to out, which holds
+// SUDOKU_LINE_SIZE bytes. Lines that don't fit are cut short.
+func writeLine(board sudoku.Board, out *C.char) {
+	line := board.Line()
+	if len(line) > C.SUDOKU_LINE_SIZE-1 {
+		line = line[:C.SUDOKU_LINE_SIZE-1]
+	}
+	buffer := unsafe.Slice((*byte)(unsafe.Pointer(out)), C.SUDOKU_LINE_SIZE)
+	buffer[copy(buffer, line)] = 0
+}
+
+// Solves a board, see the package comment.
+//
+//export SudokuSolve
+func SudokuSolve(board *C.char, solution *C.char) C.int {
+	parsed, err := sudoku.Parse([]byte(C.GoString(board)))
+	if err != nil {
+		return code(err)
+	}
+	if len(parsed) != C.SUDOKU_LINE_SIZE-1 {
+		return C.SUDOKU_INVALID
+	}
+	result, err := parsed.Solve()
+	if err != nil {
+		return code(err)
+	}
+	writeLine(result, solution)
+	return C.SUDOKU_OK
+}
+
+// Generates a puzzle, see the package comment.
+//
+//export SudokuGenerate
+func SudokuGenerate(seed C.longlong, difficulty C.int, puzzle *C.char) C.int {
+	if difficulty < 0 || difficulty > C.int(sudoku.Extreme) {
+		return C.SUDOKU_INVALID
+	}
+	writeLine(sudoku.Generate(sudoku.GenerateOptions{
+		Seed:       int64(seed),
+		Difficulty: sudoku.Difficulty(difficulty),
+	}), puzzle)
+	return C.SUDOKU_OK
+}
+
+// Rates a puzzle, see the package comment.
+//
+//export SudokuRate
+func SudokuRate(board *C.char, score *C.int, difficulty *C.int) C.int {
+	parsed, err := sudoku.Parse([]byte(C.GoString(board)))
+	if err != nil {
+		return code(err)
+	}
+	rating, err := parsed.Rate()
+	if err != nil {
+		return code(err)
+	}
+	*score = C.int(rating.Score)
+	*difficulty = C.int(rating.Difficulty)
+	return C.SUDOKU_OK
+}
@@ -0,0 +1,98 @@
+//go:build js && wasm
+
+/* This program exposes the solver to JavaScript when compiled to WebAssembly:
+ *
+ *   GOOS=js GOARCH=wasm go build -o sudoku.wasm ./cmd/sudoku-wasm
+ *
+ * Load it with wasm_exec.js from $(go env GOROOT)/lib/wasm, which defines
+ * these global functions:
+ *
+ *   solve(board)          returns the solution as an 81 character line.
+ *   generate(difficulty)  returns a puzzle as an 81 character line, the
+ *                         difficulty ("easy" to "extreme") is optional.
+ *   hint(board)           returns the next logical move as an object, ie.
+ *                         {technique:"naked single",cell:10,value:4,...}.
+ *
+ * Boards are accepted in any format sudoku.Parse understands, ie. an 81
+ * character line. On failure, the functions return {error:"..."} instead.
+ */
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"syscall/js"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+func main() {
+	js.Global().Set("solve", function(solve))
+	js.Global().Set("generate", function(generate))
+	js.Global().Set("hint", function(hint))
+
+	// Keep the functions available for the lifetime of the page.
+	select {}
+}
+
+// Wraps fn as a JavaScript function, a returned error becomes {error:"..."}.
+func function(fn func(args []js.Value) (interface{}, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		result, err := fn(args)
+		if err != nil {
+			return map[string]interface{}{"error": err.Error()}
+		}
+		return result
+	})
+}
+
+// Parses the board in the first argument.
+func board(args []js.Value) (sudoku.Board, error) {
+	if len(args) == 0 {
+		return sudoku.Parse(nil)
+	}
+	return sudoku.Parse([]byte(args[0].String()))
+}
+
+func solve(args []js.Value) (interface{}, error) {
+	board, err := board(args)
+	if err != nil {
+		return nil, err
+	}
+	solution, err := board.Solve()
+	if err != nil {
+		return nil, err
+	}
+	return solution.Line(), nil
+}
+
+func generate(args []js.Value) (interface{}, error) {
+	options := sudoku.GenerateOptions{Seed: time.Now().UnixNano() ^ rand.Int63()}
+	if len(args) > 0 && args[0].Type() == js.TypeString {
+		var err error
+		options.Difficulty, err = sudoku.ParseDifficulty(args[0].String())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sudoku.Generate(options).Line(), nil
+}
+
+func hint(args []js.Value) (interface{}, error) {
+	board, err := board(args)
+	if err != nil {
+		return nil, err
+	}
+	step, err := board.Hint()
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the step through json, so it has the same shape as elsewhere.
+	data, err := json.Marshal(step)
+	if err != nil {
+		return nil, err
+	}
+	return js.Global().Get("JSON").Call("parse", string(data)), nil
+}
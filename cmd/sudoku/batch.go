@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// A puzzle read from stdin, and the line it was read from (1 based), or its
+// position in a MessagePack stream.
+type batchJob struct {
+	line int
+	data []byte
+}
+
+// The result of solving a batch job, written as a json line.
+type batchResult struct {
+	Line     int             `json:"line"`
+	Solution json.RawMessage `json:"solution,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// Set when the error is the search giving up after --timeout.
+	Timeout bool          `json:"timeout,omitempty"`
+	Stats   *sudoku.Stats `json:"stats,omitempty"`
+
+	// The solution, written as is with --output=msgpack.
+	solution sudoku.Board
+}
+
+// The result of solving a batch job, written as MessagePack.
+type batchMsgpackResult struct {
+	Line     int          `msgpack:"line"`
+	Solution sudoku.Board `msgpack:"solution,omitempty"`
+	Error    string       `msgpack:"error,omitempty"`
+	Timeout  bool         `msgpack:"timeout,omitempty"`
+}
+
+// Reads one puzzle per line from stdin, solves them with a pool of workers
+// and writes a json object per puzzle to stdout as they are solved. With
+// --input=msgpack and --output=msgpack, the puzzles and results are streams
+// of MessagePack values instead.
+func batchCommand(args []string) {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	input := flags.String("input", "auto", "input format: auto (a puzzle per line) or msgpack")
+	output := outputFlag(flags)
+	engine := engineFlag(flags)
+	workers := flags.Int("workers", runtime.GOMAXPROCS(0), "number of puzzles solved concurrently")
+	stats := flags.Bool("stats", false, "add the statistics of the search to each result")
+	timeout := timeoutFlag(flags)
+	parseFlags(flags, args)
+	lookupOutput(*output)
+	if *input != "auto" && *input != "msgpack" {
+		fail(exitError, fmt.Errorf("Unsupported input format for batch: %s", *input))
+	}
+	if *workers < 1 {
+		fail(exitError, errors.New("--workers must be at least 1"))
+	}
+	solver := sudoku.Solver{Engine: lookupEngine(*engine), Logger: logger}
+
+	jobs := make(chan batchJob, *workers)
+	results := make(chan batchResult, *workers)
+
+	// Read the puzzles, skipping blank lines and .sdm metadata lines.
+	go func() {
+		if *input == "msgpack" {
+			readMsgpackJobs(jobs)
+			return
+		}
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			if len(scanner.Bytes()) == 0 || scanner.Bytes()[0] == '#' {
+				continue
+			}
+			jobs <- batchJob{line, append([]byte(nil), scanner.Bytes()...)}
+		}
+		if err := scanner.Err(); err != nil {
+			fail(exitIO, err)
+		}
+		close(jobs)
+	}()
+
+	// Solve them.
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				results <- solveJob(solver, *output, job, *stats, *timeout)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Write the results, flushing whenever no more are ready.
+	writer := bufio.NewWriter(os.Stdout)
+	for result := range results {
+		if *output == "msgpack" {
+			data, err := msgpack.Marshal(batchMsgpackResult{result.Line, result.solution, result.Error, result.Timeout})
+			if err != nil {
+				fail(exitError, err)
+			}
+			writer.Write(data)
+		} else {
+			data, err := json.Marshal(result)
+			if err != nil {
+				fail(exitError, err)
+			}
+			writer.Write(data)
+			writer.WriteByte('\n')
+		}
+		if len(results) == 0 {
+			if err := writer.Flush(); err != nil {
+				fail(exitIO, err)
+			}
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		fail(exitIO, err)
+	}
+}
+
+// Parses and solves a single puzzle, with the statistics of the search if
+// stats is set, giving up after timeout unless it is 0.
+func solveJob(solver sudoku.Solver, output string, job batchJob, stats bool, timeout time.Duration) batchResult {
+	result := batchResult{Line: job.line}
+	puzzle, err := sudoku.Parse(job.data)
+	var solution sudoku.Board
+	if err == nil {
+		if stats {
+			solver.Stats = &sudoku.Stats{}
+			result.Stats = solver.Stats
+		}
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		solution, err = solver.Solve(ctx, puzzle)
+		result.Timeout = errors.Is(err, context.DeadlineExceeded)
+		err = searchError(err, timeout)
+	}
+	if err == nil && output == "msgpack" {
+		result.solution = solution
+	} else if err == nil {
+		result.Solution, err = formatJSON(output, solution, puzzle)
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Reads a stream of MessagePack puzzles from stdin, numbering them from 1.
+func readMsgpackJobs(jobs chan<- batchJob) {
+	decoder := msgpack.NewDecoder(bufio.NewReader(os.Stdin))
+	for n := 1; ; n++ {
+		value, err := decoder.DecodeInterface()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			fail(exitInvalid, fmt.Errorf("Invalid MessagePack: %s", err))
+		}
+		text, err := msgpackText(value)
+		if err != nil {
+			fail(exitInvalid, err)
+		}
+		jobs <- batchJob{n, text}
+	}
+	close(jobs)
+}
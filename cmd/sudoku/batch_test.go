@@ -0,0 +1,38 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+func TestSolveJob(t *testing.T) {
+	const easy = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+	tests := []struct {
+		name     string
+		line     string
+		timeout  time.Duration
+		solution string
+		error    string
+		timedOut bool
+	}{
+		{"solved", easy, 0, "483921657967345821251876493548132976729564138136798245372689514814253769695417382", "", false},
+		{"within the timeout", easy, time.Minute, "483921657967345821251876493548132976729564138136798245372689514814253769695417382", "", false},
+		{"timeout", easy, time.Nanosecond, "", "Search stopped after --timeout=1ns", true},
+		{"invalid", "11" + easy[2:], 0, "", "Board is invalid", false},
+		{"bad size", "123", 0, "", "Board is not a supported size", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := solveJob(sudoku.Solver{}, "line", batchJob{1, []byte(test.line)}, false, test.timeout)
+			if result.Timeout != test.timedOut || !strings.HasPrefix(result.Error, test.error) || test.error == "" && result.Error != "" {
+				t.Errorf("got error %q, timeout %t, want %q, %t", result.Error, result.Timeout, test.error, test.timedOut)
+			}
+			if solution := strings.Trim(string(result.Solution), `"`); solution != test.solution {
+				t.Errorf("got solution %s, want %s", solution, test.solution)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The puzzles solved by bench, one per line: easy puzzles generated with
+// --seed=1, the top95 collection of hard puzzles and some of the hardest
+// known ones.
+//
+//go:embed corpus
+var corpora embed.FS
+
+// The corpora in the order they are run by default.
+var corpusNames = []string{"easy", "top95", "hardest"}
+
+// The result of solving a corpus, times are in microseconds.
+type benchResult struct {
+	Corpus          string  `json:"corpus"`
+	Puzzles         int     `json:"puzzles"`
+	Solves          int     `json:"solves"`
+	PuzzlesPerSec   float64 `json:"puzzles_per_second"`
+	AllocsPerPuzzle float64 `json:"allocs_per_puzzle"`
+	BytesPerPuzzle  float64 `json:"bytes_per_puzzle"`
+	P50             float64 `json:"p50_us"`
+	P90             float64 `json:"p90_us"`
+	P99             float64 `json:"p99_us"`
+	Max             float64 `json:"max_us"`
+}
+
+// Solves the embedded corpora and writes the speed, allocations and
+// latencies of the solver to stdout.
+func benchCommand(args []string) {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	output := flags.String("output", "text", "output format: text or json")
+	engine := engineFlag(flags)
+	names := flags.String("corpus", strings.Join(corpusNames, ","), "comma separated corpora to solve: "+strings.Join(corpusNames, ", "))
+	rounds := flags.Int("rounds", 3, "number of times each puzzle is solved")
+	parseFlags(flags, args)
+	if *output != "text" && *output != "json" {
+		fail(exitError, fmt.Errorf("Unknown output format: %s", *output))
+	}
+	if *rounds < 1 {
+		fail(exitError, errors.New("--rounds must be at least 1"))
+	}
+	solver := sudoku.Solver{Engine: lookupEngine(*engine)}
+
+	results := []benchResult{}
+	for _, name := range strings.Split(*names, ",") {
+		puzzles, err := readCorpus(name)
+		if err != nil {
+			fail(exitError, err)
+		}
+		result, err := bench(solver, name, puzzles, *rounds)
+		if err != nil {
+			fail(exitError, err)
+		}
+		results = append(results, result)
+	}
+
+	if *output == "json" {
+		result, err := json.Marshal(results)
+		if err != nil {
+			fail(exitError, err)
+		}
+		fmt.Printf("%s\n", result)
+		return
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "corpus\tpuzzles\tpuzzles/s\tallocs/puzzle\tbytes/puzzle\tp50\tp90\tp99\tmax\t")
+	for _, r := range results {
+		fmt.Fprintf(writer, "%s\t%d\t%.1f\t%.0f\t%.0f\t%s\t%s\t%s\t%s\t\n", r.Corpus, r.Puzzles, r.PuzzlesPerSec,
+			r.AllocsPerPuzzle, r.BytesPerPuzzle, micros(r.P50), micros(r.P90), micros(r.P99), micros(r.Max))
+	}
+	writer.Flush()
+}
+
+// Returns the puzzles of an embedded corpus, or an error if there is no
+// corpus with that name.
+func readCorpus(name string) ([]sudoku.Board, error) {
+	data, err := corpora.ReadFile("corpus/" + name + ".txt")
+	if err != nil {
+		error := fmt.Sprintf("Unknown corpus: %s, expected one of %s", name, strings.Join(corpusNames, ", "))
+		return nil, errors.New(error)
+	}
+	puzzles := []sudoku.Board{}
+	for i, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		puzzle, err := sudoku.ParseLine(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("Corpus %s line %d: %s", name, i+1, err)
+		}
+		puzzles = append(puzzles, puzzle)
+	}
+	return puzzles, nil
+}
+
+// Solves every puzzle rounds times, one at a time, and measures it.
+func bench(solver sudoku.Solver, name string, puzzles []sudoku.Board, rounds int) (benchResult, error) {
+	ctx := context.Background()
+	latencies := make([]time.Duration, 0, len(puzzles)*rounds)
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	started := time.Now()
+	for round := 0; round < rounds; round++ {
+		for i, puzzle := range puzzles {
+			start := time.Now()
+			if _, err := solver.Solve(ctx, puzzle); err != nil {
+				return benchResult{}, fmt.Errorf("Corpus %s line %d: %s", name, i+1, err)
+			}
+			latencies = append(latencies, time.Since(start))
+		}
+	}
+	elapsed := time.Since(started)
+	runtime.ReadMemStats(&after)
+
+	solves := float64(len(latencies))
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	percentile := func(p int) float64 {
+		return float64(latencies[(len(latencies)-1)*p/100]) / float64(time.Microsecond)
+	}
+	return benchResult{
+		Corpus:          name,
+		Puzzles:         len(puzzles),
+		Solves:          len(latencies),
+		PuzzlesPerSec:   solves / elapsed.Seconds(),
+		AllocsPerPuzzle: float64(after.Mallocs-before.Mallocs) / solves,
+		BytesPerPuzzle:  float64(after.TotalAlloc-before.TotalAlloc) / solves,
+		P50:             percentile(50),
+		P90:             percentile(90),
+		P99:             percentile(99),
+		Max:             percentile(100),
+	}, nil
+}
+
+// Formats a time in microseconds for the text output, ie. "1.2ms".
+func micros(us float64) string {
+	return time.Duration(us * float64(time.Microsecond)).Round(time.Microsecond).String()
+}
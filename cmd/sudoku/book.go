@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+	"github.com/dhedegaard/sudoku.go/render"
+)
+
+// The difficulties of the puzzles of a book with --difficulty=mixed, in
+// turn.
+var mixedDifficulties = []sudoku.Difficulty{sudoku.Easy, sudoku.Medium, sudoku.Hard, sudoku.Expert, sudoku.Extreme}
+
+// A puzzle of a book, with its solution and rating.
+type bookPuzzle struct {
+	puzzle, solution sudoku.Board
+	rating           sudoku.Rating
+}
+
+// Generates puzzles, sorts them from the easiest and writes them as a pdf
+// booklet with their solutions at the end.
+func bookCommand(args []string) {
+	flags := flag.NewFlagSet("book", flag.ContinueOnError)
+	count := flags.Int("count", 20, "number of puzzles")
+	difficulty := flags.String("difficulty", "mixed", "difficulty of the puzzles: easy, medium, hard, expert, extreme or mixed")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed for a reproducible book")
+	perPage := flags.Int("per-page", 4, "number of puzzles on each page")
+	solutionsPerPage := flags.Int("solutions-per-page", 6, "number of solutions on each page")
+	output := flags.String("output", "-", "file to write the pdf to, - for stdout")
+	parseFlags(flags, args)
+
+	if *count < 1 {
+		fail(exitError, errors.New("--count must be at least 1"))
+	}
+	if *perPage < 1 || *solutionsPerPage < 1 {
+		fail(exitError, errors.New("--per-page and --solutions-per-page must be at least 1"))
+	}
+	levels := mixedDifficulties
+	if *difficulty != "mixed" {
+		level, err := sudoku.ParseDifficulty(*difficulty)
+		if err != nil {
+			fail(exitError, err)
+		}
+		levels = []sudoku.Difficulty{level}
+	}
+
+	// Generate the puzzles concurrently, from consecutive seeds.
+	puzzles := make([]bookPuzzle, *count)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				options := sudoku.GenerateOptions{Seed: *seed + int64(i), Difficulty: levels[i%len(levels)]}
+				puzzle := sudoku.Generate(options)
+				solution, _ := puzzle.Solve()
+				rating, _ := puzzle.Rate()
+				puzzles[i] = bookPuzzle{puzzle, solution, rating}
+			}
+		}()
+	}
+	for i := range puzzles {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	// From the easiest to the hardest.
+	sort.SliceStable(puzzles, func(i, j int) bool {
+		return puzzles[i].rating.Score < puzzles[j].rating.Score
+	})
+	sections := []render.PDFSection{{PerPage: *perPage}, {PerPage: *solutionsPerPage}}
+	for i, p := range puzzles {
+		sections[0].Boards = append(sections[0].Boards, render.PDFBoard{
+			Board: p.puzzle, Title: fmt.Sprintf("Puzzle %d", i+1), Label: p.rating.Difficulty.String(),
+		})
+		sections[1].Boards = append(sections[1].Boards, render.PDFBoard{
+			Board: p.solution, Givens: p.puzzle, Title: fmt.Sprintf("Solution %d", i+1),
+		})
+	}
+
+	pdf := render.PDFBook(sections)
+	if *output == "-" {
+		os.Stdout.Write(pdf)
+		return
+	}
+	if err := ioutil.WriteFile(*output, pdf, 0644); err != nil {
+		fail(exitIO, err)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// The defaults of the flags read from the config file, by name: the
+// settings for every command, and tables of settings for one command each
+// by its name.
+type config map[string]interface{}
+
+// The config file read, nil until it is.
+var loadedConfig config
+
+// The commands which do not take the flags for all commands, and only their
+// own table of settings, ie. as the --output of book is a path.
+var ownSettings = map[string]bool{"play": true, "book": true, "samurai": true, "bench": true}
+
+// The commands whose flags are also read from environment variables, ie.
+// SUDOKU_ADDR for --addr, to configure them in containers.
+var envSettings = map[string]bool{"serve": true}
+
+// Returns the path of the config file, in $XDG_CONFIG_HOME or ~/.config.
+func configPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "sudoku", "config.toml")
+}
+
+// Reads the config file, or exits if it is not valid. A missing file is an
+// empty config.
+func readConfig() config {
+	if loadedConfig != nil {
+		return loadedConfig
+	}
+	loadedConfig = config{}
+	path := configPath()
+	if path == "" {
+		return loadedConfig
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return loadedConfig
+	} else if err != nil {
+		fail(exitIO, err)
+	}
+	if err := toml.Unmarshal(data, &loadedConfig); err != nil {
+		fail(exitInvalid, fmt.Errorf("Invalid config file %s: %s", path, err))
+	}
+	return loadedConfig
+}
+
+// Sets the flags of a command to their defaults in the config file: the
+// settings for every command which have a flag of the command (unless it
+// only takes its own), then the settings of its table.
+func (c config) apply(flags *flag.FlagSet) error {
+	// Set them in order, so errors are reported the same way every time.
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := c[name]
+		if _, ok := value.(map[string]interface{}); ok || flags.Lookup(name) == nil || ownSettings[flags.Name()] {
+			continue
+		}
+		if err := flags.Set(name, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("Invalid setting %s in %s: %s", name, configPath(), err)
+		}
+	}
+
+	table, _ := c[flags.Name()].(map[string]interface{})
+	names = names[:0]
+	for name := range table {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if flags.Lookup(name) == nil {
+			error := fmt.Sprintf("Unknown setting %s in [%s] of %s, %s has no --%s flag", name, flags.Name(), configPath(), flags.Name(), name)
+			return errors.New(error)
+		}
+		if err := flags.Set(name, fmt.Sprint(table[name])); err != nil {
+			return fmt.Errorf("Invalid setting %s in [%s] of %s: %s", name, flags.Name(), configPath(), err)
+		}
+	}
+	return nil
+}
+
+// Sets the flags of a command to the environment variables named after them,
+// ie. SUDOKU_RATE_LIMIT for --rate-limit, if they are set.
+func applyEnv(flags *flag.FlagSet) error {
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		name := "SUDOKU_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		value, ok := os.LookupEnv(name)
+		if !ok || err != nil {
+			return
+		}
+		if setErr := flags.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("Invalid %s: %s", name, setErr)
+		}
+	})
+	return err
+}
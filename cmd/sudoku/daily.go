@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Writes the puzzle of the day to stdout.
+func dailyCommand(args []string) {
+	flags := flag.NewFlagSet("daily", flag.ContinueOnError)
+	output := outputFlag(flags)
+	date := flags.String("date", time.Now().UTC().Format("2006-01-02"), "day of the puzzle, ie. 2024-06-01 (today in UTC by default)")
+	difficulty := flags.String("difficulty", "", "difficulty of the puzzle: easy, medium, hard, expert or extreme")
+	parseFlags(flags, args)
+	lookupOutput(*output)
+
+	day, err := time.Parse("2006-01-02", *date)
+	if err != nil {
+		fail(exitError, fmt.Errorf("Invalid date: %s, expected ie. 2024-06-01", *date))
+	}
+	var level sudoku.Difficulty
+	if *difficulty != "" {
+		level, err = sudoku.ParseDifficulty(*difficulty)
+		if err != nil {
+			fail(exitError, err)
+		}
+	}
+	write(*output, sudoku.Generate(sudoku.DailyOptions(day, level)), nil)
+}
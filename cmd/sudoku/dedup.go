@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// A puzzle read by dedup, numbered in the order read, and its fingerprint or
+// the error reading it.
+type dedupJob struct {
+	n int
+	batchJob
+	fingerprint string
+	err         error
+}
+
+// Reads one puzzle per line from stdin and writes the lines of the first
+// puzzle of each class of equivalent puzzles, which can be transformed to
+// each other, to stdout in the order read.
+func dedupCommand(args []string) {
+	flags := flag.NewFlagSet("dedup", flag.ContinueOnError)
+	workers := flags.Int("workers", runtime.GOMAXPROCS(0), "number of puzzles canonicalized concurrently")
+	parseFlags(flags, args)
+	if *workers < 1 {
+		fail(exitError, errors.New("--workers must be at least 1"))
+	}
+
+	jobs := make(chan dedupJob, *workers)
+	results := make(chan dedupJob, *workers)
+
+	// Read the puzzles, skipping blank lines and .sdm metadata lines.
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		line, n := 0, 0
+		for scanner.Scan() {
+			line++
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) == 0 || data[0] == '#' {
+				continue
+			}
+			jobs <- dedupJob{n: n, batchJob: batchJob{line, append([]byte(nil), data...)}}
+			n++
+		}
+		if err := scanner.Err(); err != nil {
+			fail(exitIO, err)
+		}
+		close(jobs)
+	}()
+
+	// Fingerprint them.
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				puzzle, err := sudoku.Parse(job.data)
+				if err == nil {
+					err = puzzle.CheckCanonical()
+				}
+				if err == nil {
+					job.fingerprint, err = puzzle.Fingerprint()
+				}
+				job.err = err
+				results <- job
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Write the first of each class in the order read, holding back the
+	// puzzles fingerprinted before the ones read before them.
+	writer := bufio.NewWriter(os.Stdout)
+	seen := map[string]bool{}
+	pending := map[int]dedupJob{}
+	next := 0
+	for result := range results {
+		pending[result.n] = result
+		for {
+			job, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if job.err != nil {
+				fmt.Fprintf(os.Stderr, "Line %d: %s\n", job.line, job.err)
+			} else if !seen[job.fingerprint] {
+				seen[job.fingerprint] = true
+				writer.Write(job.data)
+				writer.WriteByte('\n')
+			}
+		}
+		if len(results) == 0 {
+			if err := writer.Flush(); err != nil {
+				fail(exitIO, err)
+			}
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		fail(exitIO, err)
+	}
+}
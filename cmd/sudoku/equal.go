@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Reads two puzzles from files and writes whether one can be transformed to
+// the other as json to stdout, with the transform if it can.
+func equalCommand(args []string) {
+	flags := flag.NewFlagSet("equal", flag.ContinueOnError)
+	parseFlags(flags, args)
+	if flags.NArg() != 2 {
+		fail(exitError, errors.New("Usage: sudoku equal FILE FILE"))
+	}
+
+	boards := []sudoku.Board{}
+	for _, path := range flags.Args() {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fail(exitIO, err)
+		}
+		board, err := sudoku.Parse(data)
+		if err != nil {
+			fail(exitInvalid, err)
+		}
+		if err := board.CheckCanonical(); err != nil {
+			fail(exitInvalid, fmt.Errorf("Puzzle %s: %s", path, err))
+		}
+		boards = append(boards, board)
+	}
+
+	transform, equal, err := sudoku.Equivalent(boards[0], boards[1])
+	if err != nil {
+		fail(exitInvalid, err)
+	}
+	result := struct {
+		Equal     bool              `json:"equal"`
+		Transform *sudoku.Transform `json:"transform,omitempty"`
+	}{Equal: equal}
+	if equal {
+		result.Transform = &transform
+	}
+	output, err := json.Marshal(result)
+	if err != nil {
+		fail(exitError, err)
+	}
+	fmt.Printf("%s\n", output)
+}
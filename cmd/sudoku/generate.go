@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+	"github.com/dhedegaard/sudoku.go/render"
+)
+
+// Generates puzzles and writes them to stdout.
+func generateCommand(args []string) {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	output := outputFlag(flags)
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed for a reproducible puzzle")
+	symmetry := flags.String("symmetry", "none", "symmetry of the clues: rotational, mirror or none")
+	difficulty := flags.String("difficulty", "", "difficulty of the puzzle: easy, medium, hard, expert or extreme")
+	count := flags.Int("count", 1, "number of puzzles, generated from consecutive seeds")
+	perPage := flags.Int("per-page", 4, "number of puzzles on each page of a pdf")
+	boardSize := flags.Int("board-size", 9, "number of rows of the puzzles, ie. 6 or 16")
+	dbPath := dbFlag(flags)
+	variant := addVariantFlags(flags)
+	parseFlags(flags, args)
+	lookupOutput(*output)
+	db := openStore(*dbPath)
+
+	if *boardSize != 9 && (*output == "sdm" || *output == "pdf") {
+		fail(exitError, errors.New("Only 9x9 boards can be written in this format"))
+	}
+	options := sudoku.GenerateOptions{Seed: *seed, Size: *boardSize, Variant: variant.variant()}
+	if err := options.Validate(); err != nil {
+		fail(exitError, err)
+	}
+	if options.Variant.AllDots && *output != "json" {
+		fail(exitError, errors.New("Puzzles with kropki dots can only be written as json"))
+	}
+	var err error
+	options.Symmetry, err = sudoku.ParseSymmetry(*symmetry)
+	if err != nil {
+		fail(exitError, err)
+	}
+	if *difficulty != "" {
+		options.Difficulty, err = sudoku.ParseDifficulty(*difficulty)
+		if err != nil {
+			fail(exitError, err)
+		}
+	}
+
+	if *count < 1 {
+		fail(exitError, errors.New("--count must be at least 1"))
+	}
+	if *perPage < 1 {
+		fail(exitError, errors.New("--per-page must be at least 1"))
+	}
+
+	// Write a single .sdm collection or pdf sheet, or each puzzle in turn.
+	collection := sudoku.SDM{Metadata: map[byte]string{
+		'S': "sudoku.go",
+		'D': fmt.Sprintf("%d puzzles from seed %d", *count, options.Seed),
+	}}
+	sheet := []render.PDFBoard{}
+	for i := 0; i < *count; i++ {
+		puzzle, err := sudoku.GeneratePuzzleContext(context.Background(), options)
+		if err != nil {
+			fail(exitCode(err, exitError), err)
+		}
+		if db != nil && isClassic(options.Variant) {
+			if err := db.SaveGenerated(context.Background(), puzzle.Board); err != nil {
+				fail(exitIO, err)
+			}
+		}
+		switch {
+		case options.Variant.AllDots:
+			// The board along with its dots.
+			result, err := json.Marshal(puzzle)
+			if err != nil {
+				fail(exitError, err)
+			}
+			fmt.Printf("%s\n", result)
+		case *output == "sdm":
+			collection.Puzzles = append(collection.Puzzles, puzzle.Board)
+		case *output == "pdf":
+			board := render.PDFBoard{Board: puzzle.Board, Title: fmt.Sprintf("Puzzle %d", i+1)}
+			if rating, err := puzzle.Variant.Rate(puzzle.Board); err == nil {
+				board.Label = rating.Difficulty.String()
+			}
+			sheet = append(sheet, board)
+		default:
+			write(*output, puzzle.Board, nil)
+		}
+		options.Seed++
+	}
+	switch *output {
+	case "sdm":
+		fmt.Println(collection.String())
+	case "pdf":
+		os.Stdout.Write(render.PDF(sheet, *perPage))
+	}
+}
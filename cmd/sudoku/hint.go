@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+// Reads a partial board from stdin and writes the next logical move as json
+// to stdout.
+func hintCommand(args []string) {
+	flags := flag.NewFlagSet("hint", flag.ContinueOnError)
+	input := inputFlag(flags)
+	parseFlags(flags, args)
+
+	board := readClassicBoard(*input)
+
+	hint, err := board.Hint()
+	if err != nil {
+		fail(exitCode(err, exitInvalid), err)
+	}
+
+	result, err := json.Marshal(hint)
+	if err != nil {
+		fail(exitError, err)
+	}
+	fmt.Printf("%s\n", result)
+}
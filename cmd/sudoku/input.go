@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/dhedegaard/sudoku.go"
+	sudokupb "github.com/dhedegaard/sudoku.go/proto"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// An input format, parses and validates a board under the variant's rules,
+// and any the input adds to them.
+type input func(data []byte, variant sudoku.Variant) (sudoku.Board, sudoku.Variant, error)
+
+// Input formats selectable with --input.
+var inputs = map[string]input{
+	// Any of the text formats, or a json object with the board and the
+	// rules of its variant, see sudoku.Variant.ParsePuzzle.
+	"auto": func(data []byte, variant sudoku.Variant) (sudoku.Board, sudoku.Variant, error) {
+		if alphabet != "" {
+			decoded, err := alphabet.Decode(data)
+			if err != nil {
+				return nil, variant, err
+			}
+			data = decoded
+		}
+		logger.Debug("Detected input format", "format", detectFormat(data))
+		return variant.ParsePuzzle(data)
+	},
+	// The candidates of each cell of a sukaku, see
+	// sudoku.Variant.ParseSukaku.
+	"sukaku": func(data []byte, variant sudoku.Variant) (sudoku.Board, sudoku.Variant, error) {
+		return variant.ParseSukaku(data)
+	},
+	// A length delimited Board message, see proto/sudoku.proto.
+	"proto": func(data []byte, variant sudoku.Variant) (sudoku.Board, sudoku.Variant, error) {
+		message := &sudokupb.Board{}
+		if err := protodelim.UnmarshalFrom(bytes.NewReader(data), message); err != nil {
+			return nil, variant, fmt.Errorf("Invalid Board message: %s", err)
+		}
+		board := message.Sudoku()
+		if _, err := variant.IsValid(board); err != nil {
+			return nil, variant, fmt.Errorf("%w: %w", sudoku.ErrInvalidBoard, err)
+		}
+		return board, variant, nil
+	},
+	// A MessagePack value standing for any of the json formats, or the line
+	// format as a string.
+	"msgpack": func(data []byte, variant sudoku.Variant) (sudoku.Board, sudoku.Variant, error) {
+		var value interface{}
+		if err := msgpack.Unmarshal(data, &value); err != nil {
+			return nil, variant, fmt.Errorf("Invalid MessagePack: %s", err)
+		}
+		text, err := msgpackText(value)
+		if err != nil {
+			return nil, variant, err
+		}
+		logger.Debug("Detected input format", "format", detectFormat(text))
+		return variant.ParsePuzzle(text)
+	},
+}
+
+// Converts a decoded MessagePack value to text sudoku.Parse reads, a string
+// as is and anything else as json.
+func msgpackText(value interface{}) ([]byte, error) {
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(value)
+}
+
+// Returns the text format the board is read as, see sudoku.DetectFormat, or
+// "puzzle" for a json object with the rules of its variant.
+func detectFormat(data []byte) string {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return "puzzle"
+	}
+	return sudoku.DetectFormat(data)
+}
+
+// Returns the number of filled cells of the board.
+func givens(board sudoku.Board) int {
+	count := 0
+	for _, value := range board {
+		if value != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Adds the --input flag to a command.
+func inputFlag(flags *flag.FlagSet) *string {
+	return flags.String("input", "auto", "input format: auto, sukaku, proto or msgpack")
+}
+
+// Reads and parses a board from stdin in the given format, or exits if it is
+// unknown, stdin cannot be read or the board is invalid under the variant's
+// rules. Returns the board and the variant with the rules of the input.
+func readBoard(name string, variant sudoku.Variant) (sudoku.Board, sudoku.Variant) {
+	format, ok := inputs[name]
+	if !ok {
+		fail(exitError, fmt.Errorf("Unknown input format: %s", name))
+	}
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fail(exitIO, err)
+	}
+	logger.Debug("Read input", "input", name, "bytes", len(data))
+	board, variant, err := format(data, variant)
+	if err != nil {
+		fail(exitInvalid, err)
+	}
+	logger.Debug("Parsed board", "cells", len(board), "givens", givens(board), "classic", isClassic(variant))
+	return board, variant
+}
+
+// Reads a board like readBoard, but exits unless it is classic sudoku.
+func readClassicBoard(name string) sudoku.Board {
+	board, variant := readBoard(name, sudoku.Variant{})
+	if !isClassic(variant) {
+		fail(exitInvalid, errors.New("Only classic sudoku is supported"))
+	}
+	return board
+}
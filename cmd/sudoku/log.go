@@ -0,0 +1,21 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+)
+
+// The level of the messages logged, set by --log-level.
+var logLevel = new(slog.LevelVar)
+
+// Logs to stderr as json lines, like the failures, keeping stdout for the
+// results.
+var logger = slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+// Adds the --log-level flag to a command.
+func logLevelFlag(flags *flag.FlagSet) {
+	warn := new(slog.LevelVar)
+	warn.Set(slog.LevelWarn)
+	flags.TextVar(logLevel, "log-level", warn, "messages logged to stderr: debug, info, warn or error")
+}
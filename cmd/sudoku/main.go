@@ -0,0 +1,658 @@
+/* This application solves and generates sudoku boards.
+ *
+ *   sudoku [solve] [flags]  takes a sudoku board as input (stdin) and writes
+ *                           the solved board to stdout.
+ *   sudoku batch [flags]    takes one board per line as input (stdin), ie.
+ *                           a .sdm collection, solves them concurrently and
+ *                           writes a json object per board to stdout as they
+ *                           are solved, ie. {"line":1,"solution":[...]} or
+ *                           {"line":2,"error":"Board has no solution"}.
+ *   sudoku generate [flags] writes new puzzles with a unique solution to
+ *                           stdout.
+ *   sudoku minimize [flags] takes a puzzle as input (stdin) and writes it
+ *                           to stdout with every clue that is not needed for
+ *                           a unique solution removed.
+ *   sudoku serve [flags]    serves a web app, and solve, validate, generate,
+ *                           rate and hint as a json api over http, with a
+ *                           websocket streaming the search (see the server
+ *                           package), or as a gRPC service (see
+ *                           proto/sudoku.proto).
+ *   sudoku rate [flags]     takes a puzzle as input (stdin) and writes its
+ *                           difficulty as json to stdout, ie.
+ *                           {"score":120,"difficulty":"hard","technique":"x-wing"}
+ *                           It takes --variant and --regions as for solve.
+ *   sudoku hint             takes a partial board as input (stdin) and writes
+ *                           the next logical move as json to stdout, ie.
+ *                           {"technique":"naked single","cell":10,"value":4,
+ *                           "cells":[0,1,2,...]} where cells are indexes
+ *                           0-80, row by row.
+ *   sudoku samurai [flags]  takes a samurai sudoku, five overlapping 9x9
+ *                           grids, as input (stdin) and writes the solved
+ *                           grids to stdout. It is read as the 21 rows of
+ *                           the layout, with spaces between the grids, or
+ *                           as a json array of the top left, top right,
+ *                           center, bottom left and bottom right grids.
+ *   sudoku play [flags]     plays a generated puzzle in the terminal: move
+ *                           the cursor with the arrow keys or hjkl, enter
+ *                           values with 1-9 (or pencil marks, toggled with
+ *                           p), clear cells with 0, get a hint with ? and
+ *                           quit with q. Values conflicting with another in
+ *                           a row, column or box are shown in red.
+ *   sudoku repl             reads commands from stdin, a line each, and
+ *                           writes their results to stdout, to play a
+ *                           puzzle interactively or from a script:
+ *                           load PUZZLE (any format on one line), new
+ *                           [DIFFICULTY], set r3c5 7, clear r3c5, hint,
+ *                           check, undo, redo, solve, show, help and
+ *                           quit. Errors are written as "Error: ..." and
+ *                           the session goes on.
+ *   sudoku equal FILE FILE  reads a puzzle from each file and writes as
+ *                           json whether the first can be rotated,
+ *                           reflected, have lines swapped and values
+ *                           relabeled to the second, with the transform if
+ *                           it can, ie. {"equal":true,"transform":{
+ *                           "transpose":true,"rows":[...],"columns":[...],
+ *                           "values":[0,...]}}: the first puzzle, transposed
+ *                           if set, with row i taken from rows[i], column j
+ *                           from columns[j] and value v becoming values[v].
+ *                           Puzzles with boxes larger than 3x3 are invalid.
+ *   sudoku dedup [flags]    takes one puzzle per line as input (stdin), ie.
+ *                           a .sdm collection, and writes the lines of the
+ *                           first puzzle of each class of equivalent ones
+ *                           (as for equal) to stdout, in the order read.
+ *                           Lines that are not puzzles, or have boxes
+ *                           larger than 3x3, are reported on stderr and
+ *                           skipped. It takes --workers as for batch.
+ *   sudoku daily [flags]    writes the puzzle of the day to stdout, the
+ *                           same everywhere for the same day and
+ *                           difficulty: --date=2024-06-01 (today in UTC by
+ *                           default) and --difficulty=LEVEL as for
+ *                           generate.
+ *   sudoku book [flags]     generates and rates puzzles, and writes them
+ *                           from the easiest to the hardest as a pdf
+ *                           booklet, with their solutions at the end.
+ *   sudoku bench [flags]    solves reference puzzles built into the
+ *                           application (easy ones, the top95 collection
+ *                           and some of the hardest known) one at a time
+ *                           and writes the puzzles solved per second, the
+ *                           allocations per puzzle and the 50th, 90th and
+ *                           99th percentile and longest solve of each.
+ *
+ * Boards are read either as json (a flat array or 9 nested rows), as an 81
+ * character line (with . or 0 for blanks), as a SadMan Sudoku .sdk file, as
+ * a Simple Sudoku .ss grid, as csv (9 rows of cells, blank or 0 if empty) or
+ * as an f-puzzles or SudokuPad link. With --input=proto, solve, minimize,
+ * rate and hint read a length delimited Board message (see
+ * proto/sudoku.proto) instead, and with --input=msgpack a MessagePack array
+ * of 81 numbers, 9 nested rows or the line format as a string. With
+ * --input=sukaku, they read a sukaku, the puzzle given as the candidates of
+ * each cell instead of givens, as 729 characters: 9 per cell, row by row,
+ * with the value of each candidate and . or 0 in place of the others.
+ * Every command also takes other sizes up to 31x31, ie. 4x4 boards with 2x2
+ * boxes, 6x6 boards with 2x3 boxes, 12x12 boards with 3x4 boxes, 16x16 and
+ * 25x25 boards, as numbers, nested rows or a character per cell with A-Z for
+ * 10 and up, and writes them as json, grid, pretty, line, qr, qr-png, proto
+ * or msgpack.
+ * solve also reads a puzzle as a json object with the rules of its variant
+ * (see sudoku.Variant), the board being optional if there are no givens:
+ * the cages of killer sudoku, each a list of cell indexes whose values do
+ * not repeat and add up to the sum, ie.
+ * {"board":"...","cages":[{"cells":[0,1,9],"sum":12},...]}, the X and V
+ * marks of XV sudoku between pairs of adjacent cells, ie.
+ * {"x":[[0,1],...],"v":[[12,21],...]}, the white and black dots of kropki
+ * sudoku, ie. {"white":[[0,1],...],"black":[[3,4],...]}, with
+ * "alldots":true if no other adjacent cells are consecutive or double,
+ * thermometers, paths of cells from the bulb along which the values
+ * increase, ie. {"thermos":[[0,1,2,11],...]}, arrows, whose values add up
+ * to the value of their circle, ie. {"arrows":[{"circle":0,"cells":[1,2]}]},
+ * palindromes, paths of cells whose values read the same from either end,
+ * ie. {"palindromes":[[0,10,20,21],...]},
+ * or the sandwich sums outside each row and column of the values between
+ * the 1 and the 9, -1 for no clue, ie.
+ * {"sandwichrows":[2,8,-1,...],"sandwichcolumns":[...]}.
+ * If an error occurs (ie board invalid, input not valid) a json object is
+ * written to stderr and no stdout is supplied, with the exit code, the
+ * message and the cells causing it, if any, by row and column from 0, ie.
+ * {"code":2,"message":"Board is invalid: Number 5 appears twice in row 3",
+ * "cells":[{"row":2,"column":0},{"row":2,"column":4}]}. For an invalid
+ * board, cells has the cells of every problem of the board, and problems
+ * lists them with their cells and message (see sudoku.ValidationReport).
+ * The exit code tells the kind of failure apart:
+ *   1  anything else, ie. an unknown flag or output format.
+ *   2  the input is not valid, ie. not a board or a board breaking a rule.
+ *   3  the board has no solution.
+ *   4  the search did not finish within --timeout.
+ *   5  reading or writing stdin, stdout, a file or a database failed.
+ *   6  a unique solution is required but the board has several.
+ *
+ * Every command takes --log-level=debug|info|warn|error (warn by default)
+ * and logs the messages of that level and up to stderr as json lines, like
+ * the failures, ie. the input format detected, the board parsed and the
+ * solver engine searching it at debug level, and the requests answered by
+ * serve at info level. stdout only ever has the results.
+ *
+ * The defaults of the flags are read from ~/.config/sudoku/config.toml (in
+ * $XDG_CONFIG_HOME if it is set), if it exists, and flags given on the
+ * command line override them. Settings at the top apply to every command
+ * with the flag, except those which do not take the flags for all commands,
+ * and a table per command applies to that command only, ie.
+ *
+ *   output = "pretty"
+ *   solver = "dlx"
+ *
+ *   [generate]
+ *   difficulty = "hard"
+ *   symmetry = "rotational"
+ *
+ *   [serve]
+ *   addr = ":9000"
+ *   timeout = "5s"
+ *
+ * Flags for all commands:
+ *   --output=FORMAT                 the output format, one of:
+ *     json                          a flat array (default).
+ *     grid                          nested rows.
+ *     pretty                        a human readable grid.
+ *     line                          the line format.
+ *     spoken                        a sentence per row for screen readers,
+ *                                   ie. "Row one: five, blank, seven, ...".
+ *     candidates                    the candidates of each cell of the
+ *                                   puzzle, the values not used by its peers
+ *                                   (the value of filled cells), as a grid
+ *                                   (of the puzzle and not its solution with
+ *                                   solve).
+ *     sukaku                        the candidates of each cell, as read by
+ *                                   --input=sukaku.
+ *     canonical                     the line format of the smallest board
+ *                                   the puzzle can be rotated, reflected,
+ *                                   have lines swapped and values relabeled
+ *                                   to, the same for equivalent puzzles.
+ *                                   Only for boxes up to 3x3.
+ *     fingerprint                   a sha256 hash of the canonical form.
+ *     sdk, ss, sdm                  a .sdk file, a Simple Sudoku grid or a
+ *                                   .sdm collection.
+ *     csv                           9 rows of comma separated cells.
+ *     fpuzzles-url                  an f-puzzles link, with the solution if
+ *                                   it is unique.
+ *     svg, png                      an image with the givens in bold.
+ *     pdf                           a printable A4 page.
+ *     tikz                          a tikzpicture for LaTeX documents.
+ *     html                          a standalone page with the puzzle and
+ *                                   its solution (if it is unique) behind a
+ *                                   toggle.
+ *     qr, qr-png                    a qr code of the puzzle's line format,
+ *                                   to scan with a phone, as terminal blocks
+ *                                   or as an image.
+ *     proto                         a length delimited Board message (see
+ *                                   proto/sudoku.proto).
+ *     msgpack                       a MessagePack array of numbers.
+ *                                   batch embeds binary formats as base64,
+ *                                   except msgpack (see below).
+ *   --size=512                      the width and height of png and qr-png
+ *                                   images, in pixels.
+ *   --style=ascii|unicode           with --output=pretty, draw the boxes with
+ *                                   |, - and + (default) or with box
+ *                                   drawing characters, ie. ┌───┬───┐, which
+ *                                   line up in more fonts.
+ *   --no-color                      with --output=pretty or line, write a
+ *                                   solved board without colours. Written
+ *                                   to a terminal, its givens are bold and
+ *                                   the values filled in by solve cyan.
+ *   --alphabet=WORDPLAYS            read and write letters instead of the
+ *                                   values for wordoku, the first for 1 (a
+ *                                   letter per value of the board). Applies
+ *                                   to the text input formats and the
+ *                                   pretty, line, ss, sdm and csv outputs,
+ *                                   the others write numbers.
+ *
+ * Flags for solve:
+ *   --require-unique                fail unless the board has exactly one
+ *                                   solution.
+ *   --all [--max=N]                 write every solution (at most N), one
+ *                                   per line, as they are found.
+ *   --explain                       write a json object with the solution
+ *                                   and the logical steps solving it, each
+ *                                   with the technique, placed value and
+ *                                   eliminated candidates.
+ *   --stats                         write a json object with the solution
+ *                                   and the statistics of the search, ie.
+ *                                   {"solution":[...],"stats":{"nodes":32,
+ *                                   "backtracks":24,"max_depth":7,
+ *                                   "propagations":61,"elapsed_ns":406994}}
+ *                                   (see sudoku.Stats), or add them to the
+ *                                   object of --explain. The puzzle is
+ *                                   always solved, and not answered from
+ *                                   --db.
+ *   --solver=backtrack|dlx          the search engine, backtracking (default)
+ *                                   or dancing links, which is much faster
+ *                                   on hard boards and with --all.
+ *   --parallel                      search separate branches of the board
+ *                                   concurrently, on every CPU.
+ *   --timeout=10s                   give up searching after this long (no
+ *                                   limit by default).
+ *   --url=LINK                      solve the puzzle shared in an f-puzzles
+ *                                   or SudokuPad LINK instead of reading
+ *                                   stdin.
+ *   --visualize [--delay=20ms]      redraw the board on stderr as the
+ *                                   solver places and removes values,
+ *                                   pausing after each change.
+ *   --trace                         log each step of the search to stderr
+ *                                   at debug level (lowering --log-level to
+ *                                   it): the values assigned as the only
+ *                                   candidate of a cell or as a guess, the
+ *                                   candidates a rule of the variant rules
+ *                                   out when that leaves one or none, the
+ *                                   cells left without candidates and the
+ *                                   guesses backtracked, ie.
+ *                                   {"type":"eliminate","cell":{"row":1,
+ *                                   "column":0},"value":3,"depth":1,
+ *                                   "reason":"cage 2"}. Searches with
+ *                                   backtracking, on a single goroutine.
+ *   --mark-thermos                  with --output=pretty, follow each cell
+ *                                   with o for the bulb of a thermometer, =
+ *                                   for its other cells or a space.
+ *   --variant=x,windoku             solve a sudoku variant with extra rules,
+ *                                   comma separated: x for sudoku X (each
+ *                                   value once on both main diagonals),
+ *                                   windoku for hyper sudoku (each value once
+ *                                   in the four extra 3x3 windows, a cell in
+ *                                   from the corners), disjoint (each value
+ *                                   once in the cells at the same position
+ *                                   of every box), antiknight (no value
+ *                                   twice a chess knight's move apart),
+ *                                   antiking (no value twice in diagonally
+ *                                   adjacent cells), nonconsecutive (no
+ *                                   consecutive values in orthogonally
+ *                                   adjacent cells) and kropki (every
+ *                                   kropki dot is given).
+ *                                   --explain only supports classic sudoku.
+ *   --regions=MAP                   solve a jigsaw sudoku, where the boxes
+ *                                   are replaced by irregular regions. MAP
+ *                                   has a character per cell, the same for
+ *                                   cells in the same region, ie.
+ *                                   "111122222 111322224 ..." (whitespace is
+ *                                   ignored).
+ *   --db=PATH                       keep the solutions of classic puzzles
+ *                                   up to 9x9 in a SQLite database, created
+ *                                   if missing, and answer puzzles found in
+ *                                   it (or ones equivalent to them, as for
+ *                                   equal) from it instead of solving them
+ *                                   again. rate keeps the ratings the same
+ *                                   way, and generate saves the puzzles
+ *                                   generated.
+ *
+ * Flags for batch:
+ *   --solver=backtrack|dlx          as for solve.
+ *   --input=auto|msgpack            read a stream of MessagePack boards
+ *                                   instead of one board per line, numbered
+ *                                   from 1 in the results.
+ *   --output=msgpack                write a MessagePack map per board instead
+ *                                   of a json line, with the solution as an
+ *                                   array of 81 numbers.
+ *   --workers=N                     the number of boards solved concurrently,
+ *                                   the number of CPUs by default.
+ *   --stats                         add the statistics of the search to
+ *                                   each json line, as for solve.
+ *   --timeout=10s                   give up on a board after this long, its
+ *                                   line has "timeout":true along with the
+ *                                   error (no limit by default).
+ *
+ * Flags for serve, which are also read from environment variables named
+ * after them, ie. SUDOKU_ADDR=:9000 for --addr or SUDOKU_RATE_LIMIT=5 for
+ * --rate-limit, overriding the config file but not the command line:
+ *   --addr=:8080                    the address to listen on.
+ *   --solver=backtrack|dlx          as for solve.
+ *   --workers=N                     the number of goroutines searching
+ *                                   separate branches of each board, as
+ *                                   for solve --parallel (1 by default).
+ *   --timeout=10s                   the maximum time spent solving,
+ *                                   generating, rating or giving a hint
+ *                                   for a board, answered with a 503 (no
+ *                                   limit by default).
+ *   --grpc                          serve the gRPC service instead of the
+ *                                   json api.
+ *   --lambda                        serve the json api as an AWS Lambda
+ *                                   function behind API Gateway, instead of
+ *                                   listening on --addr.
+ *   --rate-limit=N                  allow each client N requests per second
+ *                                   on the api (no limit by default).
+ *   --rate-burst=N                  allow each client N requests at once
+ *                                   before limiting (--rate-limit rounded up
+ *                                   by default).
+ *   --rate-by=ip|key                tell clients apart by ip address, or by
+ *                                   the api key in their Authorization
+ *                                   header (ip by default). Limiting by key
+ *                                   requires --api-keys-file, keys with
+ *                                   their own rate are always limited by
+ *                                   key.
+ *   --api-keys-file=FILE            only allow requests with one of the api
+ *                                   keys in FILE, one per line and optionally
+ *                                   followed by its requests per second and
+ *                                   burst, ie. "3f9a2c 5 10".
+ *   --allowed-origins=ORIGINS       let browser apps on the comma separated
+ *                                   ORIGINS, ie. https://example.com, call the
+ *                                   api, or any origin with * (none by
+ *                                   default).
+ *   --db=PATH                       answer /solve and /rate from a database
+ *                                   as for solve, and save the puzzles
+ *                                   generated to it.
+ *   --db-max-puzzles=1000000        the most puzzles kept in --db, the ones
+ *                                   saved first are deleted beyond it, or 0
+ *                                   for no limit.
+ *   --redis=URL                     the same with a Redis server instead,
+ *                                   ie. redis://localhost:6379/0, shared by
+ *                                   every server using it.
+ *   --redis-expiry=720h             how long Redis keeps a puzzle after it
+ *                                   is last saved, or 0 until Redis evicts
+ *                                   it.
+ *   --pprof                         serve the profiles of net/http/pprof
+ *                                   under /debug/pprof/, ie. for
+ *                                   go tool pprof http://HOST/debug/pprof/heap.
+ *                                   They are not rate limited or checked for
+ *                                   api keys, only use it on a private
+ *                                   address.
+ *
+ * Flags for generate:
+ *   --seed=N                        generate the same puzzle every time for
+ *                                   the same seed (random by default).
+ *   --symmetry=rotational|mirror|none
+ *                                   make the clue pattern symmetric under a
+ *                                   180 degree rotation or a left to right
+ *                                   mirror (none by default).
+ *   --difficulty=easy|medium|hard|expert|extreme
+ *                                   generate a puzzle rated at the given
+ *                                   difficulty (any by default).
+ *   --count=N                       generate N puzzles from consecutive seeds,
+ *                                   with --output=sdm as a single .sdm
+ *                                   collection or with --output=pdf as a
+ *                                   single sheet (1 by default).
+ *   --per-page=N                    the number of puzzles on each page with
+ *                                   --output=pdf, with their number and
+ *                                   difficulty above them (4 by default).
+ *   --board-size=N                  the number of rows of the puzzles, up to
+ *                                   16 (9 by default). 4x4 puzzles are always
+ *                                   easy and 6x6 puzzles are never hard.
+ *   --variant=x,windoku, --regions=MAP
+ *                                   generate a puzzle for a sudoku variant,
+ *                                   as for solve. With kropki, the dots of
+ *                                   the solution are placed and the puzzle
+ *                                   is written as a json object with them,
+ *                                   which solve reads.
+ *
+ * Flags for play, which does not take the flags for all commands:
+ *   --file=PATH                     play the 9x9 puzzle in this file instead
+ *                                   of a generated one.
+ *   --seed=N, --difficulty=LEVEL    generate the puzzle as for generate.
+ *   --load=PATH, --save=PATH        resume the game saved in a file, and
+ *                                   save the game to a file when quitting,
+ *                                   as a json object of the givens, the
+ *                                   board, the pencil marks and the seconds
+ *                                   played. repl takes them as well.
+ *
+ * Flags for book, which does not take the flags for all commands:
+ *   --count=N                       the number of puzzles (20 by default).
+ *   --difficulty=LEVEL|mixed        the difficulty of the puzzles as for
+ *                                   generate, or mixed for each difficulty
+ *                                   in turn (the default).
+ *   --seed=N                        generate the same book every time for
+ *                                   the same seed (random by default).
+ *   --per-page=N                    the number of puzzles on each page (4 by
+ *                                   default).
+ *   --solutions-per-page=N          the number of solutions on each page (6
+ *                                   by default).
+ *   --output=PATH                   the file to write the pdf to (stdout by
+ *                                   default).
+ *
+ * Flags for bench, which does not take the flags for all commands:
+ *   --solver=backtrack|dlx          as for solve.
+ *   --corpus=easy,top95,hardest     the comma separated puzzle sets to solve
+ *                                   (all by default).
+ *   --rounds=N                      the number of times each puzzle is
+ *                                   solved (3 by default).
+ *   --output=text|json              a table (default), or a json array with
+ *                                   an object per puzzle set and the times
+ *                                   in microseconds.
+ *
+ * Flags for samurai, which does not take the flags for all commands:
+ *   --output=text|json              the 21 rows of the layout (default), or
+ *                                   a json array of the five grids.
+ *   --require-unique                fail unless the puzzle has exactly one
+ *                                   solution.
+ */
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+	"github.com/dhedegaard/sudoku.go/store"
+	"golang.org/x/term"
+)
+
+// The available commands, solve is used when no command is given.
+var commands = map[string]func(args []string){
+	"solve":    solveCommand,
+	"batch":    batchCommand,
+	"generate": generateCommand,
+	"minimize": minimizeCommand,
+	"rate":     rateCommand,
+	"serve":    serveCommand,
+	"hint":     hintCommand,
+	"samurai":  samuraiCommand,
+	"play":     playCommand,
+	"repl":     replCommand,
+	"equal":    equalCommand,
+	"dedup":    dedupCommand,
+	"daily":    dailyCommand,
+	"book":     bookCommand,
+	"bench":    benchCommand,
+}
+
+func main() {
+	args := os.Args[1:]
+	command := "solve"
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			command = args[0]
+			args = args[1:]
+		}
+	}
+	commands[command](args)
+}
+
+// Adds --log-level to the flags of a command and parses them, after setting
+// their defaults from the config file and, for serve, the environment. Exits
+// with 1 on invalid flags (or 0 if help was requested).
+func parseFlags(flags *flag.FlagSet, args []string) {
+	logLevelFlag(flags)
+	if err := readConfig().apply(flags); err != nil {
+		fail(exitInvalid, err)
+	}
+	if envSettings[flags.Name()] {
+		if err := applyEnv(flags); err != nil {
+			fail(exitInvalid, err)
+		}
+	}
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	} else if err != nil {
+		os.Exit(exitError)
+	}
+}
+
+// Adds the --output flag to a command, --size for the image formats and
+// --alphabet for the text ones.
+func outputFlag(flags *flag.FlagSet) *string {
+	flags.IntVar(&imageSize, "size", imageSize, "width and height of png images in pixels")
+	flags.StringVar((*string)(&alphabet), "alphabet", "", "letters to read and write instead of the values, ie. WORDPLAYS")
+	flags.Func("style", "boxes of pretty output: ascii or unicode", func(name string) error {
+		style, ok := styles[name]
+		if !ok {
+			return fmt.Errorf("Unknown style: %s", name)
+		}
+		prettyStyle = style
+		return nil
+	})
+	flags.BoolVar(&noColor, "no-color", false, "write solved boards without colours to a terminal")
+	return flags.String("output", "json", "output format: "+outputNames())
+}
+
+// The values accepted by --solver.
+var engines = map[string]sudoku.Engine{
+	"backtrack": sudoku.Backtracking,
+	"dlx":       sudoku.DancingLinks,
+}
+
+// Adds the --timeout flag to a command.
+func timeoutFlag(flags *flag.FlagSet) *time.Duration {
+	return flags.Duration("timeout", 0, "maximum time spent searching, 0 for no limit")
+}
+
+// Returns the error of a search, saying it stopped after --timeout if it
+// did.
+func searchError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("Search stopped after --timeout=%s: %w", timeout, err)
+	}
+	return err
+}
+
+// Adds the --solver flag to a command.
+func engineFlag(flags *flag.FlagSet) *string {
+	return flags.String("solver", "backtrack", "search engine: backtrack or dlx")
+}
+
+// Adds the --db flag to a command.
+func dbFlag(flags *flag.FlagSet) *string {
+	return flags.String("db", "", "SQLite database of puzzles, their solutions and ratings, to answer repeated puzzles from")
+}
+
+// Opens the database of --db, or returns nil if path is empty. Exits if it
+// cannot be opened.
+func openStore(path string) *store.Store {
+	if path == "" {
+		return nil
+	}
+	db, err := store.Open(path)
+	if err != nil {
+		fail(exitIO, err)
+	}
+	return db
+}
+
+// Returns the engine with the given name, or exits if it is unknown.
+func lookupEngine(name string) sudoku.Engine {
+	engine, ok := engines[name]
+	if !ok {
+		fail(exitError, fmt.Errorf("Unknown solver: %s", name))
+	}
+	return engine
+}
+
+// Returns the formatter for an output format, or exits if it is unknown.
+func lookupOutput(name string) output {
+	format, ok := outputs[name]
+	if !ok {
+		fail(exitError, fmt.Errorf("Unknown output format: %s", name))
+	}
+	return format
+}
+
+// Writes a board to stdout in the named format, followed by a newline unless
+// the format is binary. givens is the puzzle the board was solved from, or nil
+// if there is none.
+func write(name string, board, givens sudoku.Board) {
+	result, err := outputs[name](board, givens)
+	if err != nil {
+		fail(exitError, err)
+	}
+	if (name == "pretty" || name == "line") && givens != nil && !noColor && term.IsTerminal(int(os.Stdout.Fd())) {
+		result = colorize(result, board, givens, name == "pretty" && markedVariant.Thermos != nil)
+	}
+	if !binaryOutputs[name] {
+		result = append(result, '\n')
+	}
+	if _, err := os.Stdout.Write(result); err != nil {
+		fail(exitIO, err)
+	}
+}
+
+// The exit codes, by the kind of failure.
+const (
+	// Any other failure, ie. an unknown flag or output format.
+	exitError = 1
+	// The input or the board in it is not valid.
+	exitInvalid = 2
+	// The board has no solution.
+	exitUnsolvable = 3
+	// The search did not finish in time.
+	exitTimeout = 4
+	// Reading or writing a file, stdin or stdout, or a database failed.
+	exitIO = 5
+	// A unique solution is required, but the board has several.
+	exitNotUnique = 6
+)
+
+// Returns the exit code for an error of the solver or from reading a file,
+// or otherwise the given code.
+func exitCode(err error, otherwise int) int {
+	var pathErr *fs.PathError
+	switch {
+	case errors.Is(err, sudoku.ErrUnsolvable):
+		return exitUnsolvable
+	case errors.Is(err, sudoku.ErrNotUnique):
+		return exitNotUnique
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout
+	case errors.Is(err, sudoku.ErrInvalidBoard):
+		return exitInvalid
+	case errors.As(err, &pathErr):
+		return exitIO
+	}
+	return otherwise
+}
+
+// An error written to stderr, with the cells of the board causing it if
+// it has any, and every problem of the board if it is not valid.
+type failure struct {
+	Code     int                 `json:"code"`
+	Message  string              `json:"message"`
+	Cells    []sudoku.Coordinate `json:"cells,omitempty"`
+	Problems []sudoku.Problem    `json:"problems,omitempty"`
+}
+
+// Writes the error to stderr as a json failure and exits with code.
+func fail(code int, err error) {
+	result := failure{Code: code, Message: err.Error()}
+	var report sudoku.ValidationReport
+	var conflict sudoku.ErrConflict
+	var digit sudoku.ErrBadDigit
+	if errors.As(err, &report) {
+		// Every cell of every problem, once.
+		seen := map[sudoku.Coordinate]bool{}
+		for _, p := range report.Problems {
+			for _, cell := range p.Cells {
+				if !seen[cell] {
+					seen[cell] = true
+					result.Cells = append(result.Cells, cell)
+				}
+			}
+		}
+		result.Problems = report.Problems
+	} else if errors.As(err, &conflict) {
+		result.Cells = []sudoku.Coordinate{conflict.CellA, conflict.CellB}
+	} else if errors.As(err, &digit) {
+		result.Cells = []sudoku.Coordinate{{Row: digit.Pos / digit.Max, Column: digit.Pos % digit.Max}}
+	}
+	data, _ := json.Marshal(result)
+	fmt.Fprintf(os.Stderr, "%s\n", data)
+	os.Exit(code)
+}
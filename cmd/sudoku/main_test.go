@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Runs the command line instead of the tests when the test binary is run
+// by runMain.
+func TestMain(m *testing.M) {
+	if os.Getenv("SUDOKU_TEST_MAIN") != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// Runs the command line with args and stdin, without a config file, and
+// returns its exit code, stdout and stderr.
+func runMain(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "SUDOKU_TEST_MAIN=1", "XDG_CONFIG_HOME="+t.TempDir())
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		t.Fatal(err)
+	}
+	return cmd.ProcessState.ExitCode(), stdout.String(), stderr.String()
+}
+
+func TestExitCodes(t *testing.T) {
+	const easy = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+	const solution = "483921657967345821251876493548132976729564138136798245372689514814253769695417382"
+	empty := strings.Repeat(".", 81)
+	unsolvable := "12345678.........9" + empty[18:]
+	dir := t.TempDir()
+	puzzle := filepath.Join(dir, "puzzle.txt")
+	if err := os.WriteFile(puzzle, []byte(easy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		code  int
+	}{
+		{"solve", easy, []string{"--output=line"}, 0},
+		{"help", "", []string{"solve", "--help"}, 0},
+		{"unknown flag", easy, []string{"solve", "--unknown"}, exitError},
+		{"unknown output", easy, []string{"--output=unknown"}, exitError},
+		{"unknown solver", easy, []string{"--solver=unknown"}, exitError},
+		{"no input", "", nil, exitInvalid},
+		{"invalid board", "11" + easy[2:], nil, exitInvalid},
+		{"bad size", "123", nil, exitInvalid},
+		{"unsolvable", unsolvable, nil, exitUnsolvable},
+		{"all of an unsolvable board", unsolvable, []string{"--all"}, exitUnsolvable},
+		{"timeout", empty, []string{"--timeout=1ns"}, exitTimeout},
+		{"not unique", empty, []string{"--require-unique"}, exitNotUnique},
+		{"unique", easy, []string{"--require-unique", "--output=line"}, 0},
+		{"hint", easy, []string{"hint"}, 0},
+		{"hint a solved board", solution, []string{"hint"}, exitInvalid},
+		{"hint an unsolvable board", unsolvable, []string{"hint"}, exitUnsolvable},
+		{"rate an invalid board", "11" + easy[2:], []string{"rate"}, exitInvalid},
+		{"generate", "", []string{"generate", "--seed=1", "--output=line"}, 0},
+		{"generate a bad count", "", []string{"generate", "--count=0"}, exitError},
+		{"equal", "", []string{"equal", puzzle, puzzle}, 0},
+		{"equal without files", "", []string{"equal"}, exitError},
+		{"equal a missing file", "", []string{"equal", puzzle, filepath.Join(dir, "missing.txt")}, exitIO},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, stdout, stderr := runMain(t, test.stdin, test.args...)
+			if code != test.code {
+				t.Fatalf("got exit code %d, want %d: %s", code, test.code, stderr)
+			}
+			if code == 0 {
+				if stdout+stderr == "" {
+					t.Error("got no output")
+				}
+				return
+			}
+			result := failure{}
+			if err := json.Unmarshal([]byte(stderr), &result); err != nil {
+				// Flag errors are written by the flag package.
+				if test.name != "unknown flag" {
+					t.Errorf("got %v reading the failure %q", err, stderr)
+				}
+				return
+			}
+			if result.Code != code || result.Message == "" {
+				t.Errorf("got failure %+v for exit code %d", result, code)
+			}
+		})
+	}
+
+	// The cells of an invalid board are reported.
+	_, _, stderr := runMain(t, "11"+empty[2:])
+	result := failure{}
+	if err := json.Unmarshal([]byte(stderr), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Cells) != 2 || len(result.Problems) != 1 {
+		t.Errorf("got cells %v and problems %v, want the two 1s of the first row", result.Cells, result.Problems)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{sudoku.ErrUnsolvable, exitUnsolvable},
+		{sudoku.ErrNotUnique, exitNotUnique},
+		{fmt.Errorf("Search stopped: %w", context.DeadlineExceeded), exitTimeout},
+		{fmt.Errorf("%w: Number 1 appears twice", sudoku.ErrInvalidBoard), exitInvalid},
+		{&fs.PathError{Op: "open", Path: "puzzle.txt", Err: fs.ErrNotExist}, exitIO},
+		{errors.New("Something else"), exitError},
+	}
+	for _, test := range tests {
+		if code := exitCode(test.err, exitError); code != test.code {
+			t.Errorf("got exit code %d for %v, want %d", code, test.err, test.code)
+		}
+	}
+}
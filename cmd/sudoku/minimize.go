@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+)
+
+// Reads a puzzle from stdin and writes it to stdout with every redundant
+// clue removed.
+func minimizeCommand(args []string) {
+	flags := flag.NewFlagSet("minimize", flag.ContinueOnError)
+	input := inputFlag(flags)
+	output := outputFlag(flags)
+	parseFlags(flags, args)
+	lookupOutput(*output)
+
+	board := readClassicBoard(*input)
+
+	board, err := board.Minimize()
+	if err != nil {
+		fail(exitCode(err, exitInvalid), err)
+	}
+
+	write(*output, board, nil)
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dhedegaard/sudoku.go"
+	sudokupb "github.com/dhedegaard/sudoku.go/proto"
+	"github.com/dhedegaard/sudoku.go/render"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// An output format, returns the bytes to write to stdout for a board without
+// a trailing newline. givens is the puzzle the board was solved from, which
+// formats that tell givens and solved cells apart use, or nil if the filled
+// cells of the board are the givens.
+type output func(b, givens sudoku.Board) ([]byte, error)
+
+// The width and height of images in pixels, set with --size.
+var imageSize = 512
+
+// The variant whose thermometers pretty marks, set by solve with
+// --mark-thermos.
+var markedVariant sudoku.Variant
+
+// The symbols the text formats write instead of the values, set with
+// --alphabet.
+var alphabet sudoku.Alphabet
+
+// The boxes of the pretty format, set with --style.
+var prettyStyle = sudoku.ASCII
+
+// The values accepted by --style.
+var styles = map[string]sudoku.Style{
+	"ascii":   sudoku.ASCII,
+	"unicode": sudoku.Unicode,
+}
+
+// Set by --no-color to write the pretty and line formats without colours to
+// a terminal.
+var noColor bool
+
+// Output formats selectable with --output.
+var outputs = map[string]output{
+	"json": func(b, givens sudoku.Board) ([]byte, error) {
+		return json.Marshal(b)
+	},
+	"grid": func(b, givens sudoku.Board) ([]byte, error) {
+		return json.Marshal(b.Rows())
+	},
+	"pretty": spelled(func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte(markedVariant.PrettyStyle(b, prettyStyle)), nil
+	}),
+	"line": spelled(func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte(b.Line()), nil
+	}),
+	"spoken": func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte(b.Spoken()), nil
+	},
+	// The candidates of the puzzle, not its solution.
+	"candidates": spelled(func(b, givens sudoku.Board) ([]byte, error) {
+		if givens != nil {
+			b = givens
+		}
+		text, err := b.CandidatesString()
+		return []byte(text), err
+	}),
+	"sukaku": func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte(b.Sukaku()), nil
+	},
+	// The canonical form and fingerprint of the puzzle, not its solution.
+	"canonical": spelled(func(b, givens sudoku.Board) ([]byte, error) {
+		if givens != nil {
+			b = givens
+		}
+		canonical, err := b.Canonical()
+		if err != nil {
+			return nil, err
+		}
+		return []byte(canonical.Line()), nil
+	}),
+	"fingerprint": func(b, givens sudoku.Board) ([]byte, error) {
+		if givens != nil {
+			b = givens
+		}
+		fingerprint, err := b.Fingerprint()
+		return []byte(fingerprint), err
+	},
+	"sdk": classic(func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte((&sudoku.SDK{Puzzle: b}).String()), nil
+	}),
+	"ss": classic(spelled(func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte(b.SS()), nil
+	})),
+	"sdm": classic(spelled(func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte((&sudoku.SDM{Puzzles: []sudoku.Board{b}}).String()), nil
+	})),
+	"csv": classic(spelled(func(b, givens sudoku.Board) ([]byte, error) {
+		return []byte(b.CSV()), nil
+	})),
+	"fpuzzles-url": classic(func(b, givens sudoku.Board) ([]byte, error) {
+		// Include the solution if it is unique.
+		var solution sudoku.Board
+		if count, _ := b.CountSolutions(2); count == 1 {
+			solution, _ = b.Solve()
+		}
+		return []byte(b.FPuzzlesURL(solution)), nil
+	}),
+	"svg": classic(func(b, givens sudoku.Board) ([]byte, error) {
+		return render.SVG(b, givens), nil
+	}),
+	"png": classic(func(b, givens sudoku.Board) ([]byte, error) {
+		return render.PNG(b, givens, imageSize)
+	}),
+	"pdf": classic(func(b, givens sudoku.Board) ([]byte, error) {
+		return render.PDF([]render.PDFBoard{{Board: b, Givens: givens}}, 1), nil
+	}),
+	"tikz": classic(func(b, givens sudoku.Board) ([]byte, error) {
+		return render.TikZ(b, givens), nil
+	}),
+	"html": classic(func(b, givens sudoku.Board) ([]byte, error) {
+		// Show the puzzle, with the solution behind a toggle.
+		if givens != nil {
+			return render.HTML(givens, nil, b), nil
+		}
+		var solution sudoku.Board
+		if count, _ := b.CountSolutions(2); count == 1 {
+			solution, _ = b.Solve()
+		}
+		return render.HTML(b, nil, solution), nil
+	}),
+	// The qr codes are of the puzzle, not its solution.
+	"qr": func(b, givens sudoku.Board) ([]byte, error) {
+		if givens != nil {
+			b = givens
+		}
+		code, err := render.QRText(b)
+		return []byte(code), err
+	},
+	"qr-png": func(b, givens sudoku.Board) ([]byte, error) {
+		if givens != nil {
+			b = givens
+		}
+		return render.QRPNG(b, imageSize)
+	},
+	// A length delimited Board message, so several boards can be streamed.
+	"proto": func(b, givens sudoku.Board) ([]byte, error) {
+		buffer := bytes.NewBuffer(nil)
+		_, err := protodelim.MarshalTo(buffer, sudokupb.NewBoard(b))
+		return buffer.Bytes(), err
+	},
+	"msgpack": func(b, givens sudoku.Board) ([]byte, error) {
+		return msgpack.Marshal(b)
+	},
+}
+
+// The output formats that are not text, written without a trailing newline
+// and embedded in json as base64.
+var binaryOutputs = map[string]bool{
+	"png":     true,
+	"pdf":     true,
+	"qr-png":  true,
+	"proto":   true,
+	"msgpack": true,
+}
+
+// Wraps an output format that only supports 9x9 boards, so it fails on other
+// boards.
+func classic(format output) output {
+	return func(b, givens sudoku.Board) ([]byte, error) {
+		if len(b) != 81 {
+			return nil, errors.New("Only 9x9 boards can be written in this format")
+		}
+		return format(b, givens)
+	}
+}
+
+// Wraps a text output format so it writes the symbols of the alphabet
+// instead of the values, if one is set.
+func spelled(format output) output {
+	return func(b, givens sudoku.Board) ([]byte, error) {
+		text, err := format(b, givens)
+		if err != nil || alphabet == "" {
+			return text, err
+		}
+		spelled, err := alphabet.Format(b, string(text))
+		return []byte(spelled), err
+	}
+}
+
+// Returns text, a solved board in the pretty or line format, with the
+// givens in bold and the values the solver filled in in cyan. marked is
+// true if each cell of the pretty format is followed by a mark.
+func colorize(text []byte, b, givens sudoku.Board, marked bool) []byte {
+	result := make([]byte, 0, 2*len(text))
+	runes := []rune(string(text))
+	pos := 0
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		// Separators, and the box drawing characters of --style=unicode.
+		// Cells are any other character, ie. a letter of --alphabet.
+		if c == '|' || c == '-' || c == '+' || c == '\n' || c == ' ' || isBoxDrawing(c) || pos >= len(b) {
+			result = utf8.AppendRune(result, c)
+			continue
+		}
+		switch {
+		case givens[pos] != 0:
+			result = append(result, "\x1b[1m"...)
+		case b[pos] != 0:
+			result = append(result, "\x1b[36m"...)
+		}
+		result = utf8.AppendRune(result, c)
+		if b[pos] != 0 {
+			result = append(result, "\x1b[0m"...)
+		}
+		if marked && i+1 < len(runes) {
+			i++
+			result = utf8.AppendRune(result, runes[i])
+		}
+		pos++
+	}
+	return result
+}
+
+// Returns true if c is one of the box drawing characters, ie. ┼.
+func isBoxDrawing(c rune) bool {
+	return c >= 0x2500 && c <= 0x257f
+}
+
+// The names of the output formats, sorted and separated by "|".
+func outputNames() string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, "|")
+}
+
+// Formats a board for embedding in a json document: as json for the json
+// formats, or as a string for the others.
+func formatJSON(name string, b, givens sudoku.Board) (json.RawMessage, error) {
+	formatted, err := outputs[name](b, givens)
+	if err != nil {
+		return nil, err
+	}
+	if binaryOutputs[name] {
+		return json.Marshal(formatted)
+	}
+	if name != "json" && name != "grid" {
+		return json.Marshal(string(formatted))
+	}
+	return formatted, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+func TestColorize(t *testing.T) {
+	solution, err := sudoku.ParseLine("1234341221434321")
+	if err != nil {
+		t.Fatal(err)
+	}
+	givens, err := sudoku.ParseLine("1...............")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		text   string
+		marked bool
+	}{
+		{"line", solution.Line(), false},
+		{"letters", strings.NewReplacer("1", "α", "2", "β", "3", "γ", "4", "δ").Replace(solution.Line()), false},
+		{"pretty", solution.Pretty(sudoku.ASCII), false},
+		{"unicode", solution.Pretty(sudoku.Unicode), false},
+		{"unicode letters", strings.NewReplacer("1", "α", "2", "β", "3", "γ", "4", "δ").Replace(solution.Pretty(sudoku.Unicode)), false},
+		{"marked", sudoku.Variant{Thermos: [][]int{{0, 1}}}.PrettyStyle(solution, sudoku.Unicode), true},
+	}
+	escape := regexp.MustCompile("\x1b\\[[0-9]*m")
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := string(colorize([]byte(test.text), solution, givens, test.marked))
+			if plain := escape.ReplaceAllString(result, ""); plain != test.text {
+				t.Errorf("got %q without the colours, want %q", plain, test.text)
+			}
+			if bold := strings.Count(result, "\x1b[1m"); bold != 1 {
+				t.Errorf("got %d bold cells, want 1", bold)
+			}
+			if cyan := strings.Count(result, "\x1b[36m"); cyan != 15 {
+				t.Errorf("got %d cyan cells, want 15", cyan)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+	"golang.org/x/term"
+)
+
+// Plays a puzzle in the terminal, read from a file, generated or resumed from
+// a saved game.
+func playCommand(args []string) {
+	flags := flag.NewFlagSet("play", flag.ContinueOnError)
+	file := flags.String("file", "", "play the puzzle in this file instead of a generated one")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed for a reproducible puzzle")
+	difficulty := flags.String("difficulty", "", "difficulty of the puzzle: easy, medium, hard, expert or extreme")
+	load := flags.String("load", "", "resume the game saved in this file")
+	save := flags.String("save", "", "save the game to this file when quitting")
+	parseFlags(flags, args)
+
+	var puzzle sudoku.Board
+	var g *game
+	if *load != "" {
+		saved, err := loadGame(*load)
+		if err != nil {
+			fail(exitCode(err, exitInvalid), err)
+		}
+		g = resumeGame(saved)
+	} else if *file != "" {
+		data, err := ioutil.ReadFile(*file)
+		if err != nil {
+			fail(exitIO, err)
+		}
+		puzzle, err = sudoku.Parse(data)
+		if err != nil {
+			fail(exitInvalid, err)
+		}
+		if len(puzzle) != 81 {
+			fail(exitInvalid, errors.New("Only 9x9 boards can be played"))
+		}
+	} else {
+		options := sudoku.GenerateOptions{Seed: *seed}
+		if *difficulty != "" {
+			var err error
+			options.Difficulty, err = sudoku.ParseDifficulty(*difficulty)
+			if err != nil {
+				fail(exitError, err)
+			}
+		}
+		puzzle = sudoku.Generate(options)
+	}
+	if g == nil {
+		g = newGame(puzzle)
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fail(exitError, errors.New("play needs a terminal"))
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		fail(exitIO, err)
+	}
+	g.run(os.Stdin, os.Stdout)
+	term.Restore(fd, state)
+	fmt.Println()
+	if *save != "" {
+		if err := saveGame(*save, g.saved()); err != nil {
+			fail(exitIO, err)
+		}
+	}
+}
+
+// A puzzle being played.
+type game struct {
+	givens sudoku.Board
+	board  sudoku.Board
+	// The pencil marks of each cell, bit v for value v.
+	pencil []uint16
+	cursor int
+	// True while digits toggle pencil marks instead of placing values.
+	pencilMode bool
+	// Shown below the board, ie. the last hint.
+	message string
+	// The seconds played before the game was resumed, and when it was.
+	elapsed float64
+	started time.Time
+}
+
+func newGame(puzzle sudoku.Board) *game {
+	board := make(sudoku.Board, len(puzzle))
+	copy(board, puzzle)
+	return &game{givens: puzzle, board: board, pencil: make([]uint16, len(puzzle)), started: time.Now()}
+}
+
+// Returns the game saved, to continue playing it.
+func resumeGame(saved savedGame) *game {
+	g := newGame(saved.Givens)
+	copy(g.board, saved.Board)
+	for pos, marks := range saved.PencilMarks {
+		for _, val := range marks {
+			g.pencil[pos] |= 1 << uint(val)
+		}
+	}
+	g.elapsed = saved.Elapsed
+	return g
+}
+
+// Returns the game to save, to resume it later.
+func (g *game) saved() savedGame {
+	saved := savedGame{Givens: g.givens, Board: g.board, Elapsed: played(g.elapsed, g.started).Seconds()}
+	saved.PencilMarks = make([][]int, len(g.pencil))
+	for pos, bits := range g.pencil {
+		for val := 1; val <= 9; val++ {
+			if bits&(1<<uint(val)) != 0 {
+				saved.PencilMarks[pos] = append(saved.PencilMarks[pos], val)
+			}
+		}
+	}
+	return saved
+}
+
+// Draws the game and handles keys read from in until it is quit.
+func (g *game) run(in io.Reader, out io.Writer) {
+	buffer := make([]byte, 16)
+	for {
+		io.WriteString(out, g.render())
+		n, err := in.Read(buffer)
+		if err != nil {
+			return
+		}
+		if !g.key(string(buffer[:n])) {
+			return
+		}
+	}
+}
+
+// Handles a key, or the escape sequence of one, returns false to quit.
+func (g *game) key(k string) bool {
+	g.message = ""
+	switch k {
+	case "q", "\x03":
+		return false
+	case "\x1b[A", "k":
+		g.move(-1, 0)
+	case "\x1b[B", "j":
+		g.move(1, 0)
+	case "\x1b[C", "l":
+		g.move(0, 1)
+	case "\x1b[D", "h":
+		g.move(0, -1)
+	case "p":
+		g.pencilMode = !g.pencilMode
+	case "?":
+		g.hint()
+	case "0", ".", " ", "x", "\x7f":
+		g.enter(0)
+	default:
+		if len(k) == 1 && k[0] >= '1' && k[0] <= '9' {
+			g.enter(int(k[0] - '0'))
+		}
+	}
+	return true
+}
+
+// Moves the cursor, wrapping around the edges.
+func (g *game) move(dy int, dx int) {
+	y, x := g.cursor/9, g.cursor%9
+	g.cursor = (y+dy+9)%9*9 + (x+dx+9)%9
+}
+
+// Places val at the cursor, or toggles it as a pencil mark, 0 clears the
+// cell.
+func (g *game) enter(val int) {
+	switch {
+	case g.givens[g.cursor] != 0:
+		g.message = "That cell is a given"
+	case val == 0:
+		g.board[g.cursor] = 0
+		g.pencil[g.cursor] = 0
+	case g.pencilMode:
+		g.pencil[g.cursor] ^= 1 << uint(val)
+	default:
+		g.board[g.cursor] = val
+		g.pencil[g.cursor] = 0
+		if g.solved() {
+			g.message = "Solved!"
+		}
+	}
+}
+
+// Places the value of the next logical step.
+func (g *game) hint() {
+	step, err := g.board.Hint()
+	switch {
+	case errors.Is(err, sudoku.ErrUnsolvable):
+		g.message = "The board has no solution, a value is wrong"
+	case err != nil:
+		g.message = err.Error()
+	default:
+		g.board[step.Cell] = step.Value
+		g.pencil[step.Cell] = 0
+		g.cursor = step.Cell
+		g.message = fmt.Sprintf("Hint: %s places %d at r%dc%d", step.Technique, step.Value, step.Cell/9+1, step.Cell%9+1)
+		if g.solved() {
+			g.message += ", solved!"
+		}
+	}
+}
+
+// Returns true when every cell has a value and none conflict.
+func (g *game) solved() bool {
+	for _, val := range g.board {
+		if val == 0 {
+			return false
+		}
+	}
+	return len(conflicts(g.board)) == 0
+}
+
+// Returns the cells of a 9x9 board whose value is also in another cell of
+// their row, column or box.
+func conflicts(b sudoku.Board) map[int]bool {
+	result := map[int]bool{}
+	found, _ := b.Conflicts()
+	for _, conflict := range found {
+		for _, cell := range conflict.Cells {
+			result[cell.Row*9+cell.Column] = true
+		}
+	}
+	return result
+}
+
+// Returns the screen: the board with each cell as 3 rows of 3 characters,
+// the value in the middle or the pencil marks in their places, and the
+// status below it.
+func (g *game) render() string {
+	conflicting := conflicts(g.board)
+	lines := []string{}
+	for y := 0; y < 9; y++ {
+		if y > 0 && y%3 == 0 {
+			lines = append(lines, strings.Repeat("-", 11)+"-+-"+strings.Repeat("-", 11)+"-+-"+strings.Repeat("-", 11))
+		}
+		for row := 0; row < 3; row++ {
+			line := ""
+			for x := 0; x < 9; x++ {
+				if x > 0 && x%3 == 0 {
+					line += " | "
+				} else if x > 0 {
+					line += " "
+				}
+				line += g.cell(y*9+x, row, conflicting)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	mode := "off"
+	if g.pencilMode {
+		mode = "on"
+	}
+	lines = append(lines, "",
+		"Arrows or hjkl move, 1-9 enter a value, 0 clears, ? gives a hint, q quits.",
+		"p toggles pencil marks, which are "+mode+". Played for "+played(g.elapsed, g.started).Round(time.Second).String()+".",
+		g.message)
+	return "\x1b[H\x1b[2J" + strings.Join(lines, "\r\n")
+}
+
+// Returns a row of the 3x3 characters of a cell, with its colours: givens
+// in bold, entries in cyan, conflicting values in red and the cursor
+// reversed.
+func (g *game) cell(pos int, row int, conflicting map[int]bool) string {
+	text := []byte("   ")
+	style := ""
+	switch {
+	case g.board[pos] != 0:
+		if row == 1 {
+			text[1] = byte('0' + g.board[pos])
+		}
+		switch {
+		case conflicting[pos]:
+			style = "\x1b[31m"
+		case g.givens[pos] != 0:
+			style = "\x1b[1m"
+		default:
+			style = "\x1b[36m"
+		}
+	default:
+		for i := range text {
+			if val := row*3 + i + 1; g.pencil[pos]&(1<<uint(val)) != 0 {
+				text[i] = byte('0' + val)
+			}
+		}
+		style = "\x1b[2m"
+	}
+	if pos == g.cursor {
+		style += "\x1b[7m"
+	}
+	return style + string(text) + "\x1b[0m"
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Reads a puzzle from stdin and writes its rating as json to stdout.
+func rateCommand(args []string) {
+	flags := flag.NewFlagSet("rate", flag.ContinueOnError)
+	input := inputFlag(flags)
+	dbPath := dbFlag(flags)
+	variant := addVariantFlags(flags)
+	parseFlags(flags, args)
+	db := openStore(*dbPath)
+
+	board, rules := readBoard(*input, variant.variant())
+
+	// Rate, or answer from the database if it has the puzzle.
+	var rating sudoku.Rating
+	found := false
+	cached := db != nil && isClassic(rules)
+	var err error
+	if cached {
+		rating, found, err = db.Rating(context.Background(), board)
+		if err != nil {
+			fail(exitIO, err)
+		}
+	}
+	if !found {
+		rating, err = rules.Rate(board)
+		if err != nil {
+			fail(exitCode(err, exitInvalid), err)
+		}
+		if cached {
+			if err := db.SaveRating(context.Background(), board, rating); err != nil {
+				fail(exitIO, err)
+			}
+		}
+	}
+
+	result, err := json.Marshal(rating)
+	if err != nil {
+		fail(exitError, err)
+	}
+	fmt.Printf("%s\n", result)
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+	"golang.org/x/term"
+)
+
+// Reads commands from stdin, a line each, and writes their results to
+// stdout.
+func replCommand(args []string) {
+	flags := flag.NewFlagSet("repl", flag.ContinueOnError)
+	load := flags.String("load", "", "resume the game saved in this file")
+	save := flags.String("save", "", "save the game to this file when stopping")
+	parseFlags(flags, args)
+
+	s := &session{started: time.Now()}
+	if *load != "" {
+		saved, err := loadGame(*load)
+		if err != nil {
+			fail(exitCode(err, exitInvalid), err)
+		}
+		s.givens, s.board, s.notes, s.elapsed = saved.Givens, saved.Board, saved.Notes, saved.Elapsed
+		s.history = sudoku.NewHistory(s.board)
+	}
+	prompt := ""
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		prompt = "> "
+	}
+	s.run(os.Stdin, os.Stdout, prompt)
+	if *save != "" && s.board != nil {
+		saved := savedGame{Givens: s.givens, Board: s.board, Notes: s.notes, Elapsed: played(s.elapsed, s.started).Seconds()}
+		if err := saveGame(*save, saved); err != nil {
+			fail(exitIO, err)
+		}
+	}
+}
+
+// A puzzle being solved with the commands of the repl.
+type session struct {
+	givens sudoku.Board
+	board  sudoku.Board
+	// The moves made on the board, for undo and redo.
+	history *sudoku.History
+	// The notes of a game resumed with --load, kept to save them again.
+	notes sudoku.Notes
+	// The seconds played before the game was resumed, and when it was.
+	elapsed float64
+	started time.Time
+}
+
+// The action of a command of the repl, returns the text to write.
+type replAction func(s *session, args []string) (string, error)
+
+// The commands of the repl, with the arguments they take and what they do.
+var replCommands = map[string]struct {
+	usage string
+	run   replAction
+}{
+	"load":  {"load PUZZLE       play a 9x9 puzzle in any format read on one line", (*session).load},
+	"new":   {"new [DIFFICULTY]  play a generated puzzle", (*session).generate},
+	"set":   {"set r3c5 7        place a value", (*session).set},
+	"clear": {"clear r3c5        clear a cell", (*session).clear},
+	"hint":  {"hint              show the next logical step", (*session).hint},
+	"check": {"check             show conflicting cells, or if a value is wrong", (*session).check},
+	"undo":  {"undo              undo the last change", (*session).undo},
+	"redo":  {"redo              redo the last change undone", (*session).redo},
+	"solve": {"solve             fill in the solution", (*session).solve},
+	"show":  {"show              show the board", (*session).show},
+}
+
+// Runs the commands read from in until it ends or quit or exit is read,
+// writing prompt before each.
+func (s *session) run(in io.Reader, out io.Writer, prompt string) {
+	scanner := bufio.NewScanner(in)
+	for {
+		io.WriteString(out, prompt)
+		if !scanner.Scan() {
+			return
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "quit", "exit":
+			return
+		case "help":
+			fmt.Fprintln(out, replHelp())
+			continue
+		}
+		command, ok := replCommands[fields[0]]
+		if !ok {
+			fmt.Fprintf(out, "Error: Unknown command: %s, see help\n", fields[0])
+			continue
+		}
+		if s.board == nil && fields[0] != "load" && fields[0] != "new" {
+			fmt.Fprintln(out, "Error: No puzzle, load one or start a new one")
+			continue
+		}
+		result, err := command.run(s, fields[1:])
+		if err != nil {
+			fmt.Fprintf(out, "Error: %s\n", err)
+			continue
+		}
+		fmt.Fprintln(out, result)
+	}
+}
+
+// Starts playing the puzzle.
+func (s *session) start(puzzle sudoku.Board) (string, error) {
+	if len(puzzle) != 81 {
+		return "", errors.New("Only 9x9 boards can be played")
+	}
+	s.givens = puzzle
+	s.board = make(sudoku.Board, len(puzzle))
+	copy(s.board, puzzle)
+	s.history = sudoku.NewHistory(puzzle)
+	s.notes = sudoku.Notes{}
+	s.elapsed, s.started = 0, time.Now()
+	return s.board.String(), nil
+}
+
+func (s *session) load(args []string) (string, error) {
+	puzzle, err := sudoku.Parse([]byte(strings.Join(args, " ")))
+	if err != nil {
+		return "", err
+	}
+	return s.start(puzzle)
+}
+
+func (s *session) generate(args []string) (string, error) {
+	options := sudoku.GenerateOptions{Seed: time.Now().UnixNano()}
+	if len(args) > 0 {
+		var err error
+		options.Difficulty, err = sudoku.ParseDifficulty(args[0])
+		if err != nil {
+			return "", err
+		}
+	}
+	return s.start(sudoku.Generate(options))
+}
+
+// Returns the index of a cell written as ie. r3c5.
+func parseCell(text string) (int, error) {
+	var y, x int
+	if n, err := fmt.Sscanf(text, "r%dc%d", &y, &x); n != 2 || err != nil || y < 1 || y > 9 || x < 1 || x > 9 {
+		return 0, fmt.Errorf("Not a cell: %s, expected ie. r3c5", text)
+	}
+	return (y-1)*9 + x - 1, nil
+}
+
+// Sets cell to val, remembering the move for undo.
+func (s *session) change(cell string, val int) (string, error) {
+	pos, err := parseCell(cell)
+	if err != nil {
+		return "", err
+	}
+	if s.givens[pos] != 0 {
+		return "", fmt.Errorf("%s is a given", cell)
+	}
+	return s.play(sudoku.Move{Cell: pos, Value: val})
+}
+
+// Makes the moves, together, and returns the board.
+func (s *session) play(moves ...sudoku.Move) (string, error) {
+	if err := s.history.Play(moves...); err != nil {
+		return "", err
+	}
+	s.board = s.history.Board()
+	return s.board.String(), nil
+}
+
+func (s *session) set(args []string) (string, error) {
+	var val int
+	if len(args) != 2 {
+		return "", errors.New("Usage: set r3c5 7")
+	}
+	if n, err := fmt.Sscanf(args[1], "%d", &val); n != 1 || err != nil || val < 1 || val > 9 {
+		return "", fmt.Errorf("Not a value: %s", args[1])
+	}
+	return s.change(args[0], val)
+}
+
+func (s *session) clear(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("Usage: clear r3c5")
+	}
+	return s.change(args[0], 0)
+}
+
+func (s *session) hint(args []string) (string, error) {
+	step, err := s.board.Hint()
+	if errors.Is(err, sudoku.ErrUnsolvable) {
+		return "", errors.New("The board has no solution, a value is wrong")
+	} else if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s: r%dc%d is %d", step.Technique, step.Cell/9+1, step.Cell%9+1, step.Value), nil
+}
+
+func (s *session) check(args []string) (string, error) {
+	if cells := conflicts(s.board); len(cells) > 0 {
+		names := []string{}
+		for pos := range cells {
+			names = append(names, fmt.Sprintf("r%dc%d", pos/9+1, pos%9+1))
+		}
+		sort.Strings(names)
+		return "Conflicts at " + strings.Join(names, ", "), nil
+	}
+	if _, err := s.board.Solve(); err != nil {
+		return "The board has no solution, a value is wrong", nil
+	}
+	for _, val := range s.board {
+		if val == 0 {
+			return "No conflicts", nil
+		}
+	}
+	return "Solved!", nil
+}
+
+func (s *session) undo(args []string) (string, error) {
+	if _, ok := s.history.Undo(); !ok {
+		return "", errors.New("Nothing to undo")
+	}
+	s.board = s.history.Board()
+	return s.board.String(), nil
+}
+
+func (s *session) redo(args []string) (string, error) {
+	if _, ok := s.history.Redo(); !ok {
+		return "", errors.New("Nothing to redo")
+	}
+	s.board = s.history.Board()
+	return s.board.String(), nil
+}
+
+func (s *session) solve(args []string) (string, error) {
+	solution, err := s.board.Solve()
+	if err != nil {
+		return "", err
+	}
+	moves := []sudoku.Move{}
+	for pos, val := range solution {
+		if s.board[pos] != val {
+			moves = append(moves, sudoku.Move{Cell: pos, Value: val})
+		}
+	}
+	return s.play(moves...)
+}
+
+func (s *session) show(args []string) (string, error) {
+	return s.board.String(), nil
+}
+
+// Returns the usage of every command, a line each.
+func replHelp() string {
+	lines := []string{}
+	for _, command := range replCommands {
+		lines = append(lines, command.usage)
+	}
+	lines = append(lines, "help              list the commands", "quit              stop")
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
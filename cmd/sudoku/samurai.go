@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Reads a samurai sudoku from stdin and writes its solved grids to stdout.
+func samuraiCommand(args []string) {
+	flags := flag.NewFlagSet("samurai", flag.ContinueOnError)
+	output := flags.String("output", "text", "output format: text or json")
+	requireUnique := flags.Bool("require-unique", false, "fail if the puzzle has more than one solution")
+	parseFlags(flags, args)
+	if *output != "text" && *output != "json" {
+		fail(exitError, fmt.Errorf("Unknown output format: %s", *output))
+	}
+
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fail(exitIO, err)
+	}
+	puzzle, err := sudoku.ParseSamurai(data)
+	if err != nil {
+		fail(exitInvalid, err)
+	}
+
+	// Reject ambiguous puzzles, the count is capped at 2 as that is enough.
+	if *requireUnique {
+		count, err := puzzle.CountSolutions(2)
+		if err != nil {
+			fail(exitCode(err, exitInvalid), err)
+		}
+		if count > 1 {
+			fail(exitNotUnique, sudoku.ErrNotUnique)
+		}
+	}
+
+	solution, err := puzzle.Solve()
+	if err != nil {
+		fail(exitCode(err, exitInvalid), err)
+	}
+
+	if *output == "json" {
+		result, err := json.Marshal(solution)
+		if err != nil {
+			fail(exitError, err)
+		}
+		fmt.Printf("%s\n", result)
+		return
+	}
+	fmt.Print(solution)
+}
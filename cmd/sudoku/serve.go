@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+
+	"github.com/dhedegaard/sudoku.go/server"
+	"github.com/dhedegaard/sudoku.go/store"
+)
+
+// Serves the solver over http until the process is stopped.
+func serveCommand(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	engine := engineFlag(flags)
+	workers := flags.Int("workers", 1, "number of goroutines searching separate branches of each board")
+	timeout := flags.Duration("timeout", 0, "maximum time spent solving, generating, rating or giving a hint for a board, 0 for no limit")
+	grpc := flags.Bool("grpc", false, "serve the gRPC service instead of the json api")
+	lambdaMode := flags.Bool("lambda", false, "serve the json api as an AWS Lambda function, behind API Gateway")
+	rateLimit := flags.Float64("rate-limit", 0, "requests per second allowed per client, 0 for no limit")
+	rateBurst := flags.Int("rate-burst", 0, "requests a client can make at once (--rate-limit rounded up by default)")
+	rateBy := flags.String("rate-by", "ip", "tell clients apart by ip or by api key")
+	keysFile := flags.String("api-keys-file", "", "file with the api keys allowed to use the api, and their quotas")
+	origins := flags.String("allowed-origins", "", "comma separated origins of browser apps allowed to call the api, or *")
+	dbPath := dbFlag(flags)
+	dbMax := flags.Int("db-max-puzzles", 1000000, "most puzzles kept in --db, deleting the oldest beyond it, 0 for no limit")
+	redisURL := flags.String("redis", "", "Redis server shared by several servers to answer repeated puzzles from, ie. redis://localhost:6379/0")
+	redisExpiry := flags.Duration("redis-expiry", 30*24*time.Hour, "how long --redis keeps a puzzle after it is last saved, 0 until Redis evicts it")
+	profiling := flags.Bool("pprof", false, "serve cpu and heap profiles under /debug/pprof/")
+	parseFlags(flags, args)
+	if *profiling && (*grpc || *lambdaMode) {
+		fail(exitError, errors.New("--pprof cannot be combined with --grpc or --lambda"))
+	}
+
+	srv := &server.Server{Timeout: *timeout, RateLimit: *rateLimit, RateBurst: *rateBurst, Profiling: *profiling}
+	switch {
+	case *dbPath != "" && *redisURL != "":
+		fail(exitError, errors.New("--db cannot be combined with --redis"))
+	case *dbMax < 0:
+		fail(exitError, errors.New("--db-max-puzzles must be at least 0"))
+	case *redisExpiry < 0:
+		fail(exitError, errors.New("--redis-expiry must be at least 0"))
+	case *dbPath != "":
+		db := openStore(*dbPath)
+		db.MaxPuzzles = *dbMax
+		srv.Cache = db
+	case *redisURL != "":
+		cache, err := store.OpenRedis(*redisURL)
+		if err != nil {
+			fail(exitIO, err)
+		}
+		cache.Expiry = *redisExpiry
+		srv.Cache = cache
+	}
+	switch *rateBy {
+	case "ip":
+	case "key":
+		// Only the keys of the file are trusted to tell clients apart.
+		if *keysFile == "" {
+			fail(exitError, errors.New("--rate-by=key requires --api-keys-file"))
+		}
+		srv.RateByKey = true
+	default:
+		fail(exitError, errors.New("Unknown --rate-by: "+*rateBy))
+	}
+	if *origins != "" {
+		srv.AllowedOrigins = strings.Split(*origins, ",")
+	}
+	if *keysFile != "" {
+		data, err := ioutil.ReadFile(*keysFile)
+		if err != nil {
+			fail(exitIO, err)
+		}
+		srv.APIKeys, err = server.ParseAPIKeys(data)
+		if err != nil {
+			fail(exitInvalid, err)
+		}
+	}
+	srv.Solver.Engine = lookupEngine(*engine)
+	if *workers < 1 {
+		fail(exitError, errors.New("--workers must be at least 1"))
+	}
+	srv.Solver.Workers = *workers
+	srv.Logger = logger
+	go srv.WarmUp()
+	if *lambdaMode {
+		lambda.Start(srv.Lambda)
+		return
+	}
+	if *grpc {
+		listener, err := net.Listen("tcp", *addr)
+		if err != nil {
+			fail(exitIO, err)
+		}
+		logger.Info("Serving the gRPC service", "addr", listener.Addr().String())
+		fail(exitIO, srv.GRPCServer().Serve(listener))
+	}
+	logger.Info("Serving the json api", "addr", *addr, "solver", srv.Solver.Engine.String(), "workers", srv.Solver.Workers)
+	fail(exitIO, http.ListenAndServe(*addr, srv.Handler()))
+}
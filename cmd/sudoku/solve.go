@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Read from stdin. Write to stdout, or stderr and return non-0 return code.
+func solveCommand(args []string) {
+	flags := flag.NewFlagSet("solve", flag.ContinueOnError)
+	input := inputFlag(flags)
+	output := outputFlag(flags)
+	requireUnique := flags.Bool("require-unique", false, "fail if the board has more than one solution")
+	all := flags.Bool("all", false, "write every solution, one per line")
+	max := flags.Int("max", 0, "with --all, stop after this many solutions (0 for no limit)")
+	explain := flags.Bool("explain", false, "write the logical solving steps along with the solution")
+	stats := flags.Bool("stats", false, "write the statistics of the search along with the solution")
+	engine := engineFlag(flags)
+	parallel := flags.Bool("parallel", false, "search separate branches on every CPU")
+	timeout := timeoutFlag(flags)
+	link := flags.String("url", "", "solve the puzzle in an f-puzzles or SudokuPad link instead of reading stdin")
+	markThermos := flags.Bool("mark-thermos", false, "mark the cells of thermometers in pretty output")
+	visualize := flags.Bool("visualize", false, "redraw the board on stderr as the solver places and removes values")
+	delay := flags.Duration("delay", 20*time.Millisecond, "with --visualize, the pause after each change")
+	trace := flags.Bool("trace", false, "log each value assigned, candidate eliminated and guess backtracked to stderr")
+	dbPath := dbFlag(flags)
+	variant := addVariantFlags(flags)
+	parseFlags(flags, args)
+	lookupOutput(*output)
+	if *trace && logLevel.Level() > slog.LevelDebug {
+		// The steps are logged at debug level, so show them.
+		logLevel.Set(slog.LevelDebug)
+	}
+	db := openStore(*dbPath)
+
+	solver := sudoku.Solver{Engine: lookupEngine(*engine), Variant: variant.variant(), Logger: logger}
+	if *parallel {
+		solver.Workers = runtime.GOMAXPROCS(0)
+	}
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	// Exits with an error of the search, saying why it stopped early.
+	failSearch := func(err error) {
+		err = searchError(err, *timeout)
+		fail(exitCode(err, exitInvalid), err)
+	}
+
+	// Read stdin, unless given a link.
+	var board sudoku.Board
+	var err error
+	if *link != "" {
+		board, err = sudoku.ParseURL(*link)
+		if err != nil {
+			fail(exitInvalid, err)
+		}
+	} else {
+		board, solver.Variant = readBoard(*input, solver.Variant)
+	}
+	if *explain && !isClassic(solver.Variant) {
+		fail(exitError, errors.New("--explain only supports classic sudoku"))
+	}
+	if *markThermos {
+		markedVariant = solver.Variant
+	}
+	if *visualize && *parallel {
+		fail(exitError, errors.New("--visualize cannot be combined with --parallel"))
+	}
+	if *stats && *all {
+		fail(exitError, errors.New("--stats cannot be combined with --all"))
+	}
+	if *stats {
+		solver.Stats = &sudoku.Stats{}
+	}
+	if *trace && *visualize {
+		fail(exitError, errors.New("--trace cannot be combined with --visualize"))
+	}
+	if *trace {
+		solver.Trace = func(step sudoku.TraceStep) {
+			logger.Debug("Search step", "step", step)
+		}
+	}
+
+	// Reject ambiguous boards, the count is capped at 2 as that is enough.
+	if *requireUnique {
+		count, err := solver.CountSolutions(ctx, board, 2)
+		if err != nil {
+			failSearch(err)
+		}
+		if count > 1 {
+			fail(exitNotUnique, sudoku.ErrNotUnique)
+		}
+	}
+
+	if *visualize {
+		solver.Observe = visualizer(board, solver.Variant, *delay)
+	}
+
+	// Stream all solutions, or fail if there are none.
+	if *all {
+		count := 0
+		err = solver.EachSolution(ctx, board, *max, func(solution sudoku.Board) {
+			write(*output, solution, board)
+			count++
+		})
+		if err != nil {
+			failSearch(err)
+		}
+		if count == 0 {
+			fail(exitUnsolvable, sudoku.ErrUnsolvable)
+		}
+		return
+	}
+
+	// solve, or fail, answering from the database if it has the puzzle.
+	puzzle := board
+	found := false
+	cached := db != nil && isClassic(solver.Variant) && !*visualize && !*stats && !*trace
+	if cached {
+		board, found, err = db.Solution(ctx, puzzle)
+		if err != nil {
+			fail(exitCode(err, exitIO), err)
+		}
+	}
+	if !found {
+		board, err = solver.Solve(ctx, puzzle)
+		if err != nil {
+			failSearch(err)
+		}
+		if cached {
+			if err := db.SaveSolution(ctx, puzzle, board); err != nil {
+				fail(exitIO, err)
+			}
+		}
+	}
+
+	if *explain {
+		writeExplained(*output, puzzle, board, solver.Stats)
+		return
+	}
+	if *stats {
+		writeStats(*output, puzzle, board, solver.Stats)
+		return
+	}
+
+	// write the result.
+	write(*output, board, puzzle)
+}
+
+// Returns an observer that redraws the board on stderr with each change
+// during the search, pausing for delay after each.
+func visualizer(board sudoku.Board, variant sudoku.Variant, delay time.Duration) func(sudoku.Event) {
+	current := make(sudoku.Board, len(board))
+	copy(current, board)
+	return func(event sudoku.Event) {
+		if event.Kind == sudoku.Place {
+			current[event.Cell] = event.Value
+		} else {
+			current[event.Cell] = 0
+		}
+		// Move to the top left and clear the screen below it.
+		fmt.Fprintf(os.Stderr, "\x1b[H\x1b[J%s\n", variant.PrettyStyle(current, prettyStyle))
+		time.Sleep(delay)
+	}
+}
+
+// Writes the solution along with the logical steps solving the puzzle as a
+// json object, ie. {"solution":[...],"steps":[...],"logical":true}. Logical
+// is false if the steps stop short of the solution, as guessing is needed.
+// The solution is embedded as json, or as a string for non-json formats.
+// The statistics of the search are added if not nil.
+func writeExplained(output string, puzzle sudoku.Board, solution sudoku.Board, stats *sudoku.Stats) {
+	steps, err := puzzle.SolveLogical()
+	if err != nil && err != sudoku.ErrStuck {
+		fail(exitError, err)
+	}
+	logical := err == nil
+
+	formatted, err := formatJSON(output, solution, puzzle)
+	if err != nil {
+		fail(exitError, err)
+	}
+
+	result, err := json.Marshal(struct {
+		Solution json.RawMessage `json:"solution"`
+		Steps    []sudoku.Step   `json:"steps"`
+		Logical  bool            `json:"logical"`
+		Stats    *sudoku.Stats   `json:"stats,omitempty"`
+	}{formatted, steps, logical, stats})
+	if err != nil {
+		fail(exitError, err)
+	}
+	fmt.Printf("%s\n", result)
+}
+
+// Writes the solution along with the statistics of the search as a json
+// object, ie. {"solution":[...],"stats":{"nodes":12,...}}. The solution is
+// embedded as for writeExplained.
+func writeStats(output string, puzzle sudoku.Board, solution sudoku.Board, stats *sudoku.Stats) {
+	formatted, err := formatJSON(output, solution, puzzle)
+	if err != nil {
+		fail(exitError, err)
+	}
+
+	result, err := json.Marshal(struct {
+		Solution json.RawMessage `json:"solution"`
+		Stats    *sudoku.Stats   `json:"stats"`
+	}{formatted, stats})
+	if err != nil {
+		fail(exitError, err)
+	}
+	fmt.Printf("%s\n", result)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The state of a game, saved with --save to resume it with --load, ie.
+// {"givens":[...],"board":[...],"pencilmarks":[[1,2],null,...],"elapsed":95.5}.
+type savedGame struct {
+	Givens sudoku.Board `json:"givens"`
+	// The givens and the values entered.
+	Board sudoku.Board `json:"board"`
+	sudoku.Notes
+	// The time played, in seconds.
+	Elapsed float64 `json:"elapsed"`
+}
+
+// Reads a saved game, or returns an error if it is not valid.
+func loadGame(path string) (savedGame, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return savedGame{}, err
+	}
+	saved := savedGame{}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return savedGame{}, fmt.Errorf("Invalid saved game: %s", err)
+	}
+	if len(saved.Givens) != 81 || len(saved.Board) != 81 {
+		return savedGame{}, errors.New("Invalid saved game: only 9x9 boards can be played")
+	}
+	if _, err := saved.Givens.IsValid(); err != nil {
+		return savedGame{}, fmt.Errorf("Invalid saved game: %s", err)
+	}
+	for pos, val := range saved.Givens {
+		if val != 0 && saved.Board[pos] != val {
+			error := fmt.Sprintf("Invalid saved game: the board differs from the givens at position: %d", pos)
+			return savedGame{}, errors.New(error)
+		}
+		if saved.Board[pos] < 0 || saved.Board[pos] > 9 {
+			error := fmt.Sprintf("Invalid saved game: the board has an invalid value at position: %d", pos)
+			return savedGame{}, errors.New(error)
+		}
+	}
+	if saved.Notes, err = sudoku.ParseNotes(data, saved.Board); err != nil {
+		return savedGame{}, fmt.Errorf("Invalid saved game: %s", err)
+	}
+	return saved, nil
+}
+
+// Writes a saved game.
+func saveGame(path string, saved savedGame) error {
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Returns the time played, saved before and since started.
+func played(elapsed float64, started time.Time) time.Duration {
+	return time.Duration(elapsed*float64(time.Second)) + time.Since(started)
+}
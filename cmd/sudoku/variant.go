@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The variants selectable with --variant, each adding its rules.
+var variants = map[string]func(v *sudoku.Variant){
+	"classic": func(v *sudoku.Variant) {},
+	"x": func(v *sudoku.Variant) {
+		v.Diagonals = true
+	},
+	"windoku": func(v *sudoku.Variant) {
+		v.Windows = true
+	},
+	"disjoint": func(v *sudoku.Variant) {
+		v.Disjoint = true
+	},
+	"antiknight": func(v *sudoku.Variant) {
+		v.AntiKnight = true
+	},
+	"antiking": func(v *sudoku.Variant) {
+		v.AntiKing = true
+	},
+	"nonconsecutive": func(v *sudoku.Variant) {
+		v.NonConsecutive = true
+	},
+	"kropki": func(v *sudoku.Variant) {
+		v.AllDots = true
+	},
+}
+
+// The flags selecting the rules of a variant.
+type variantFlags struct {
+	names   *string
+	regions *string
+}
+
+// Adds the --variant and --regions flags to a command.
+func addVariantFlags(flags *flag.FlagSet) variantFlags {
+	return variantFlags{
+		names:   flags.String("variant", "classic", "comma separated rules of the variant: classic, x, windoku, disjoint, antiknight, antiking, nonconsecutive or kropki"),
+		regions: flags.String("regions", "", "replace the boxes with these regions, a character per cell"),
+	}
+}
+
+// Returns true unless the variant adds rules to classic sudoku.
+func isClassic(variant sudoku.Variant) bool {
+	return reflect.DeepEqual(variant, sudoku.Variant{})
+}
+
+// Returns the variant with the rules of the flags, or exits if they are
+// unknown or invalid.
+func (f variantFlags) variant() sudoku.Variant {
+	variant := sudoku.Variant{}
+	for _, name := range strings.Split(*f.names, ",") {
+		rules, ok := variants[strings.TrimSpace(name)]
+		if !ok {
+			fail(exitError, fmt.Errorf("Unknown variant: %s", name))
+		}
+		rules(&variant)
+	}
+	if *f.regions != "" {
+		var err error
+		variant.Regions, err = sudoku.ParseRegions(*f.regions)
+		if err != nil {
+			fail(exitInvalid, err)
+		}
+	}
+	return variant
+}
@@ -0,0 +1,120 @@
+package sudoku
+
+import (
+	"fmt"
+)
+
+// A cell of a board, by its row and column counted from 0.
+type Coordinate struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+// Returns the cell as "r1c3", from 1.
+func (c Coordinate) String() string {
+	return fmt.Sprintf("r%dc%d", c.Row+1, c.Column+1)
+}
+
+// Two cells whose values break a rule of the board together, ie. the same
+// value twice in a row.
+type Conflict struct {
+	Cells [2]Coordinate `json:"cells"`
+	// The rule broken, ie. "Number 5 appears twice in row 3".
+	Rule string `json:"rule"`
+}
+
+// Returns every pair of cells whose values break a rule of the board, or an
+// error if the board has an unsupported size or values out of range.
+func (b Board) Conflicts() ([]Conflict, error) {
+	s, err := b.shape()
+	if err != nil {
+		return nil, err
+	}
+	return b.conflicts(s)
+}
+
+// Returns every pair of cells whose values break a rule of the variant, like
+// Board.Conflicts. Rules on more than two cells, ie. cages, are not broken
+// by a pair and are left to IsValid.
+func (v Variant) Conflicts(b Board) ([]Conflict, error) {
+	s, err := b.shape()
+	if err != nil {
+		return nil, err
+	}
+	s, err = v.shape(s)
+	if err != nil {
+		return nil, err
+	}
+	return b.conflicts(s)
+}
+
+// Returns the conflicts of the board, which has the shape s.
+func (b Board) conflicts(s *shape) ([]Conflict, error) {
+	if err := b.checkRange(s); err != nil {
+		return nil, err
+	}
+
+	result := []Conflict{}
+	pair := s.conflict
+	// Cells sharing several units, ie. a row and a box, conflict once.
+	seen := map[[2]int]bool{}
+	for unit, cells := range s.unitCells {
+		for i, a := range cells {
+			for _, c := range cells[i+1:] {
+				if b[a] == 0 || b[a] != b[c] {
+					continue
+				}
+				first, second := a, c
+				if first > second {
+					first, second = second, first
+				}
+				if !seen[[2]int{first, second}] {
+					seen[[2]int{first, second}] = true
+					result = append(result, pair(first, second, fmt.Sprintf("Number %d appears twice in %s", b[a], s.unitName(unit))))
+				}
+			}
+		}
+	}
+	for _, c := range s.constraints {
+		if rule, ok := c.(*pairRule); ok {
+			if err := rule.check(b); err != nil {
+				result = append(result, pair(rule.a, rule.b, err.Error()))
+			}
+		}
+	}
+	return result, nil
+}
+
+// Returns the conflict of the cells at a and c breaking the rule.
+func (s *shape) conflict(a int, c int, rule string) Conflict {
+	return Conflict{[2]Coordinate{{a / s.size, a % s.size}, {c / s.size, c % s.size}}, rule}
+}
+
+// Returned (wrapped, see ValidationReport) for a board with values breaking
+// a rule together at cells CellA and CellB, ie. the same value twice in a
+// unit. errors.Is(err, ErrConflict{}) matches any of them.
+type ErrConflict struct {
+	CellA, CellB Coordinate
+	// The rule broken, ie. "Number 5 appears twice in row 3".
+	Rule string
+}
+
+func (e ErrConflict) Error() string {
+	return e.Rule
+}
+
+// Returns true if target is an ErrConflict with the same fields, or the zero
+// ErrConflict.
+func (e ErrConflict) Is(target error) bool {
+	t, ok := target.(ErrConflict)
+	return ok && (t == ErrConflict{} || t == e)
+}
+
+// Returns the name of a unit in errors, ie. "row 3".
+func (s *shape) unitName(unit int) string {
+	if unit >= 3*s.size {
+		return s.extraNames[unit-3*s.size]
+	}
+	kind := []string{"row", "column", s.boxName}[unit/s.size]
+	return fmt.Sprintf("%s %d", kind, unit%s.size+1)
+}
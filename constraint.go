@@ -0,0 +1,579 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// The values of the cells of a board being solved, 0 for empty cells.
+type values interface {
+	value(pos int) int
+}
+
+func (b Board) value(pos int) int {
+	return b[pos]
+}
+
+// The cells of a grid.
+type gridValues []int8
+
+func (g gridValues) value(pos int) int {
+	return int(g[pos])
+}
+
+func (g *logicGrid) value(pos int) int {
+	return g.board[pos]
+}
+
+// A rule of a variant on a group of cells, beyond each value appearing once
+// in a unit, ie. the sum of a killer cage.
+type constraint interface {
+	// The cells the rule applies to.
+	cells() []int
+	// Returns the candidates of the empty cell pos, one of the cells, that
+	// can still satisfy the rule with the values of the other cells, as a
+	// bitmask. Exact when pos is the only empty cell.
+	allowed(v values, pos int) uint32
+	// Returns an error if the filled cells break the rule.
+	check(v values) error
+	// Names the rule, ie. "cage 3".
+	rule() string
+}
+
+// Returns a copy of the shape with extra constraints.
+func (s *shape) withConstraints(constraints []constraint) *shape {
+	result := *s
+	result.constraints = append(append([]constraint(nil), s.constraints...), constraints...)
+	result.cellConstraints = make([][]int, len(s.cellConstraints))
+	for pos := range result.cellConstraints {
+		result.cellConstraints[pos] = append([]int(nil), s.cellConstraints[pos]...)
+	}
+	for i, c := range constraints {
+		for _, pos := range c.cells() {
+			result.cellConstraints[pos] = append(result.cellConstraints[pos], len(s.constraints)+i)
+		}
+	}
+	return &result
+}
+
+// The sum of the values in a killer cage, which never repeat.
+type cageSum struct {
+	name string
+	cage []int
+	sum  int
+	// The largest value.
+	size int
+}
+
+func (c *cageSum) cells() []int {
+	return c.cage
+}
+
+func (c *cageSum) rule() string {
+	return c.name
+}
+
+func (c *cageSum) allowed(v values, pos int) uint32 {
+	remaining, empty := c.sum, 0
+	used := uint32(0)
+	for _, cell := range c.cage {
+		if val := v.value(cell); val != 0 {
+			remaining -= val
+			used |= 1 << uint(val)
+		} else {
+			empty++
+		}
+	}
+
+	// The other empty cells take distinct unused values, so what is left
+	// after val must be between their smallest and largest sums.
+	allowed := uint32(0)
+	for val := 1; val <= c.size && val <= remaining; val++ {
+		if used&(1<<uint(val)) != 0 {
+			continue
+		}
+		low, high, n := 0, 0, 0
+		for other := 1; other <= c.size && n < empty-1; other++ {
+			if other != val && used&(1<<uint(other)) == 0 {
+				low += other
+				n++
+			}
+		}
+		n = 0
+		for other := c.size; other >= 1 && n < empty-1; other-- {
+			if other != val && used&(1<<uint(other)) == 0 {
+				high += other
+				n++
+			}
+		}
+		if n == empty-1 && low <= remaining-val && remaining-val <= high {
+			allowed |= 1 << uint(val)
+		}
+	}
+	return allowed
+}
+
+func (c *cageSum) check(v values) error {
+	total, empty := 0, 0
+	for _, cell := range c.cage {
+		if val := v.value(cell); val != 0 {
+			total += val
+		} else {
+			empty++
+		}
+	}
+	if total+empty > c.sum || empty == 0 && total != c.sum {
+		error := fmt.Sprintf("The values in %s do not add up to %d", c.name, c.sum)
+		return errors.New(error)
+	}
+	return nil
+}
+
+// Two cells whose values must be related, ie. not consecutive in
+// non-consecutive sudoku, or adding up to 10 for an X of XV sudoku.
+type pairRule struct {
+	name string
+	a, b int
+	// The values allowed next to each value, as bitmasks.
+	masks []uint32
+	// Describes the values of cells breaking the rule, ie. "are consecutive".
+	broken string
+}
+
+// Returns the values allowed next to each value from 1 to size, as bitmasks,
+// when values x and y are allowed next to each other if related(x, y) is
+// true. The relation must go both ways.
+func relation(size int, related func(x int, y int) bool) []uint32 {
+	masks := make([]uint32, size+1)
+	for x := 1; x <= size; x++ {
+		for y := 1; y <= size; y++ {
+			if related(x, y) {
+				masks[x] |= 1 << uint(y)
+			}
+		}
+	}
+	return masks
+}
+
+func (c *pairRule) cells() []int {
+	return []int{c.a, c.b}
+}
+
+func (c *pairRule) rule() string {
+	return c.name
+}
+
+func (c *pairRule) allowed(v values, pos int) uint32 {
+	other := c.a
+	if pos == c.a {
+		other = c.b
+	}
+	val := v.value(other)
+	if val == 0 {
+		return ^uint32(0)
+	}
+	return c.masks[val]
+}
+
+func (c *pairRule) check(v values) error {
+	a, b := v.value(c.a), v.value(c.b)
+	if a != 0 && b != 0 && c.masks[a]&(1<<uint(b)) == 0 {
+		error := fmt.Sprintf("Numbers %d and %d %s in %s", a, b, c.broken, c.name)
+		return errors.New(error)
+	}
+	return nil
+}
+
+// A thermometer, the values strictly increase from the bulb.
+type thermo struct {
+	name string
+	// The cells from the bulb.
+	path []int
+	// The largest value.
+	size int
+}
+
+func (c *thermo) cells() []int {
+	return c.path
+}
+
+func (c *thermo) rule() string {
+	return c.name
+}
+
+func (c *thermo) allowed(v values, pos int) uint32 {
+	i := 0
+	for c.path[i] != pos {
+		i++
+	}
+
+	// Leave room for the smaller values before and the larger ones after.
+	low, high := i+1, c.size-(len(c.path)-1-i)
+	for j, cell := range c.path {
+		val := v.value(cell)
+		switch {
+		case val == 0:
+		case j < i && val+i-j > low:
+			low = val + i - j
+		case j > i && val-(j-i) < high:
+			high = val - (j - i)
+		}
+	}
+	if low > high {
+		return 0
+	}
+	return (1<<uint(high+1) - 1) &^ (1<<uint(low) - 1)
+}
+
+func (c *thermo) check(v values) error {
+	for i, cell := range c.path {
+		val := v.value(cell)
+		if val == 0 {
+			continue
+		}
+		broken := val < i+1 || val > c.size-(len(c.path)-1-i)
+		for j := i + 1; j < len(c.path); j++ {
+			if next := v.value(c.path[j]); next != 0 && next-val < j-i {
+				broken = true
+			}
+		}
+		if broken {
+			error := fmt.Sprintf("The values on %s cannot increase from the bulb", c.name)
+			return errors.New(error)
+		}
+	}
+	return nil
+}
+
+// The candidates of a cell, the values it can have.
+type candidateSet struct {
+	pos int
+	// The candidates as a bitmask.
+	mask uint32
+}
+
+func (c *candidateSet) cells() []int {
+	return []int{c.pos}
+}
+
+func (c *candidateSet) rule() string {
+	return fmt.Sprintf("the candidates of cell %d", c.pos)
+}
+
+func (c *candidateSet) allowed(v values, pos int) uint32 {
+	return c.mask
+}
+
+func (c *candidateSet) check(v values) error {
+	if val := v.value(c.pos); val != 0 && c.mask&(1<<uint(val)) == 0 {
+		error := fmt.Sprintf("Number %d is not a candidate of cell %d", val, c.pos)
+		return errors.New(error)
+	}
+	return nil
+}
+
+// An arrow, the values of its cells add up to the value of its circle.
+type arrow struct {
+	name string
+	// The circle, then the cells of the arrow.
+	path []int
+	// The largest value.
+	size int
+}
+
+func (c *arrow) cells() []int {
+	return c.path
+}
+
+func (c *arrow) rule() string {
+	return c.name
+}
+
+func (c *arrow) allowed(v values, pos int) uint32 {
+	circle, total, empty := v.value(c.path[0]), 0, 0
+	for _, cell := range c.path[1:] {
+		if cell == pos {
+			continue
+		}
+		if val := v.value(cell); val != 0 {
+			total += val
+		} else {
+			empty++
+		}
+	}
+
+	// The other empty cells of the arrow take values from 1 to size, which
+	// may repeat.
+	if pos == c.path[0] {
+		return c.between(total+empty, total+empty*c.size)
+	}
+	if circle == 0 {
+		return c.between(1, c.size-total-empty)
+	}
+	return c.between(circle-total-empty*c.size, circle-total-empty)
+}
+
+// Returns the values from low to high, as a bitmask of candidates.
+func (c *arrow) between(low int, high int) uint32 {
+	low, high = max(low, 1), min(high, c.size)
+	if low > high {
+		return 0
+	}
+	return (1<<uint(high+1) - 1) &^ (1<<uint(low) - 1)
+}
+
+func (c *arrow) check(v values) error {
+	circle, total, empty := v.value(c.path[0]), 0, 0
+	for _, cell := range c.path[1:] {
+		if val := v.value(cell); val != 0 {
+			total += val
+		} else {
+			empty++
+		}
+	}
+	broken := total+empty > c.size
+	if circle != 0 {
+		broken = total+empty > circle || empty == 0 && total != circle
+	}
+	if broken {
+		error := fmt.Sprintf("The values on %s do not add up to its circle", c.name)
+		return errors.New(error)
+	}
+	return nil
+}
+
+// The sandwich sum of a row or column, the values between the 1 and the
+// largest value add up to it.
+type sandwich struct {
+	name string
+	line []int
+	sum  int
+	// The largest value.
+	size int
+	// The values other than 1 and the largest, as bits.
+	middle uint32
+	// The numbers of cells between the 1 and the largest value that can add
+	// up to the sum, as bits.
+	gaps uint32
+	// The values between the 1 and the largest value in some way of adding
+	// up to the sum, and the ones between them in every way.
+	inside, required uint32
+}
+
+// Returns the sandwich sum of the cells of line, with the ways of adding up
+// to it worked out once. Its gaps are 0 if no values add up to it.
+func newSandwich(name string, line []int, sum int, size int) *sandwich {
+	c := &sandwich{name: name, line: line, sum: sum, size: size}
+	c.middle = (1<<uint(size) - 1) &^ 3
+
+	// The number of ways the middle values add up to each sum, by the
+	// number of values used.
+	ways := make([][]int, size-1)
+	for count := range ways {
+		ways[count] = make([]int, sum+1)
+	}
+	ways[0][0] = 1
+	for val := 2; val < size; val++ {
+		for count := len(ways) - 1; count > 0; count-- {
+			for total := sum; total >= val; total-- {
+				ways[count][total] += ways[count-1][total-val]
+			}
+		}
+	}
+	all := make([]int, sum+1)
+	for count := range ways {
+		if ways[count][sum] > 0 {
+			c.gaps |= 1 << uint(count)
+		}
+		for total := range all {
+			all[total] += ways[count][total]
+		}
+	}
+	if c.gaps == 0 {
+		return c
+	}
+
+	// The ways without val, taking the ways using it back out.
+	without := make([]int, sum+1)
+	for val := 2; val < size; val++ {
+		for total := range without {
+			without[total] = all[total]
+			if total >= val {
+				without[total] -= without[total-val]
+			}
+		}
+		if val <= sum && without[sum-val] > 0 {
+			c.inside |= 1 << uint(val)
+		}
+		if without[sum] == 0 {
+			c.required |= 1 << uint(val)
+		}
+	}
+	return c
+}
+
+func (c *sandwich) cells() []int {
+	return c.line
+}
+
+func (c *sandwich) rule() string {
+	return c.name
+}
+
+// The values of a line with a sandwich sum.
+type sandwichLine struct {
+	values [maxSize]int
+	// Where the 1 and the largest value are, -1 if they are not placed.
+	first, last int
+	// The values placed, as bits.
+	used uint32
+}
+
+// Returns the values of the line.
+func (c *sandwich) read(v values) sandwichLine {
+	l := sandwichLine{first: -1, last: -1}
+	for i, cell := range c.line {
+		if val := v.value(cell); val != 0 {
+			c.place(&l, i, val)
+		}
+	}
+	return l
+}
+
+// Places val at index at of the line.
+func (c *sandwich) place(l *sandwichLine, at int, val int) {
+	l.values[at] = val
+	l.used |= 1 << uint(val)
+	switch val {
+	case 1:
+		l.first = at
+	case c.size:
+		l.last = at
+	}
+}
+
+func (c *sandwich) allowed(v values, pos int) uint32 {
+	at := 0
+	for c.line[at] != pos {
+		at++
+	}
+	l := c.read(v)
+	if l.first < 0 && l.last < 0 {
+		return c.unplaced(&l, at)
+	}
+	allowed := uint32(0)
+	for val := 1; val <= c.size; val++ {
+		if l.used&(1<<uint(val)) != 0 {
+			continue
+		}
+		next := l
+		c.place(&next, at, val)
+		if c.feasible(&next) {
+			allowed |= 1 << uint(val)
+		}
+	}
+	return allowed
+}
+
+func (c *sandwich) check(v values) error {
+	l := c.read(v)
+	if !c.feasible(&l) {
+		error := fmt.Sprintf("The values between 1 and %d in %s cannot add up to %d", c.size, c.name, c.sum)
+		return errors.New(error)
+	}
+	return nil
+}
+
+// Returns false if the values of the line cannot have the sum between the 1
+// and the largest value.
+func (c *sandwich) feasible(l *sandwichLine) bool {
+	switch {
+	case l.first >= 0 && l.last >= 0:
+		return c.fits(l, l.first, l.last)
+	case l.first >= 0 || l.last >= 0:
+		// The other end must go in an empty cell the sum fits before.
+		end := l.first + l.last + 1
+		for i := range c.line {
+			if l.values[i] == 0 && c.fits(l, end, i) {
+				return true
+			}
+		}
+		return false
+	}
+	return c.unplaced(l, -1) != 0
+}
+
+// Returns the values allowed at index at of a line without its 1 and its
+// largest value, from the pairs of empty cells they can go in. With an at
+// of -1, returns non-zero if there is any such pair.
+func (c *sandwich) unplaced(l *sandwichLine, at int) uint32 {
+	ends := uint32(1)<<1 | 1<<uint(c.size)
+	outside := c.middle &^ c.required
+	allowed := uint32(0)
+	for a := range c.line {
+		if l.values[a] != 0 {
+			continue
+		}
+		for b := a + 1; b < len(c.line); b++ {
+			if l.values[b] != 0 || !c.fits(l, a, b) {
+				continue
+			}
+			switch {
+			case at < 0:
+				return 1
+			case at == a || at == b:
+				allowed |= ends
+			case a < at && at < b:
+				allowed |= c.inside &^ l.used
+			default:
+				allowed |= outside &^ l.used
+			}
+		}
+	}
+	return allowed
+}
+
+// Returns true if the values between indexes a and b of the line can add up
+// to the sum, the empty cells taking distinct values from 2 to size-1 not
+// used elsewhere in the line, and no value needed between them is outside.
+func (c *sandwich) fits(l *sandwichLine, a int, b int) bool {
+	if a > b {
+		a, b = b, a
+	}
+	if c.gaps&(1<<uint(b-a-1)) == 0 {
+		return false
+	}
+	total, empty := 0, 0
+	for i, val := range l.values[:len(c.line)] {
+		switch {
+		case i == a || i == b:
+		case i < a || i > b:
+			if c.required&(1<<uint(val)) != 0 {
+				return false
+			}
+		case val == 0:
+			empty++
+		case c.inside&(1<<uint(val)) == 0:
+			return false
+		default:
+			total += val
+		}
+	}
+
+	// The smallest and the largest sums of the values left for the empty
+	// cells.
+	low, high := 0, 0
+	small, large := c.middle&^l.used, c.middle&^l.used
+	for ; empty > 0; empty-- {
+		if small == 0 {
+			return false
+		}
+		low += bits.TrailingZeros32(small)
+		small &= small - 1
+		top := 31 - bits.LeadingZeros32(large)
+		high += top
+		large &^= 1 << uint(top)
+	}
+	return total+low <= c.sum && c.sum <= total+high
+}
@@ -0,0 +1,106 @@
+package sudoku
+
+import (
+	"testing"
+)
+
+// Returns the candidates of cell pos allowed by every rule of the variant on
+// the cell, as a bitmask, and the first rule the board breaks.
+func checkConstraints(t *testing.T, v Variant, b Board, pos int) (uint32, error) {
+	t.Helper()
+	s, ok := shapeOf(len(b))
+	if !ok {
+		t.Fatalf("no shape for %d cells", len(b))
+	}
+	s, err := v.shape(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := uint32(0)
+	for value := 1; value <= s.size; value++ {
+		allowed |= 1 << uint(value)
+	}
+	for _, i := range s.cellConstraints[pos] {
+		allowed &= s.constraints[i].allowed(b, pos)
+	}
+	for _, c := range s.constraints {
+		if err := c.check(b); err != nil {
+			return allowed, err
+		}
+	}
+	return allowed, nil
+}
+
+// Returns the values as a bitmask of candidates.
+func mask(values ...int) uint32 {
+	result := uint32(0)
+	for _, val := range values {
+		result |= 1 << uint(val)
+	}
+	return result
+}
+
+func TestConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		variant Variant
+		// The filled cells of an otherwise empty 9x9 board.
+		cells   map[int]int
+		pos     int
+		allowed uint32
+		broken  bool
+	}{
+		{"cage", Variant{Cages: []Cage{{[]int{0, 1, 2}, 6}}}, nil, 0, mask(1, 2, 3), false},
+		{"cage with a value", Variant{Cages: []Cage{{[]int{0, 1, 2}, 6}}}, map[int]int{1: 1}, 0, mask(2, 3), false},
+		{"cage of two", Variant{Cages: []Cage{{[]int{0, 1}, 17}}}, nil, 0, mask(8, 9), false},
+		{"cage adds up", Variant{Cages: []Cage{{[]int{0, 1, 2}, 6}}}, map[int]int{0: 1, 1: 2, 2: 3}, 3, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), false},
+		{"cage too large", Variant{Cages: []Cage{{[]int{0, 1, 2}, 6}}}, map[int]int{0: 5}, 1, 0, true},
+		{"cage too small", Variant{Cages: []Cage{{[]int{0, 1, 2}, 6}}}, map[int]int{0: 1, 1: 2, 2: 4}, 3, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), true},
+		{"cage without a sum", Variant{Cages: []Cage{{[]int{0, 1}, 0}}}, map[int]int{1: 4}, 0, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), false},
+		{"non-consecutive", Variant{NonConsecutive: true}, map[int]int{1: 5}, 0, mask(1, 2, 3, 5, 7, 8, 9), false},
+		{"consecutive", Variant{NonConsecutive: true}, map[int]int{0: 4, 1: 5}, 2, mask(1, 2, 3, 5, 7, 8, 9), true},
+		{"x mark", Variant{XMarks: [][2]int{{0, 1}}}, map[int]int{1: 3}, 0, mask(7), false},
+		{"x mark broken", Variant{XMarks: [][2]int{{1, 0}}}, map[int]int{0: 3, 1: 6}, 2, mask(1, 2, 3, 5, 6, 7, 8, 9), true},
+		{"v mark", Variant{VMarks: [][2]int{{0, 1}}}, map[int]int{1: 1}, 0, mask(4), false},
+		{"xv without a mark", Variant{XMarks: [][2]int{{0, 1}}}, map[int]int{9: 3}, 0, mask(1, 3, 4, 5, 6, 8, 9), false},
+		{"xv unmarked pair", Variant{VMarks: [][2]int{{0, 1}}}, map[int]int{0: 3, 9: 7}, 18, mask(1, 2, 4, 5, 6, 7, 8, 9), true},
+		{"white dot", Variant{WhiteDots: [][2]int{{0, 1}}}, map[int]int{1: 5}, 0, mask(4, 6), false},
+		{"black dot", Variant{BlackDots: [][2]int{{0, 1}}}, map[int]int{1: 4}, 0, mask(2, 8), false},
+		{"black dot broken", Variant{BlackDots: [][2]int{{0, 1}}}, map[int]int{0: 3, 1: 4}, 2, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), true},
+		{"all dots", Variant{AllDots: true}, map[int]int{1: 4}, 0, mask(1, 4, 6, 7, 9), false},
+		{"all dots broken", Variant{AllDots: true}, map[int]int{0: 3, 1: 6}, 2, mask(1, 2, 4, 6, 8, 9), true},
+		{"thermo", Variant{Thermos: [][]int{{0, 1, 2}}}, nil, 1, mask(2, 3, 4, 5, 6, 7, 8), false},
+		{"thermo with a value", Variant{Thermos: [][]int{{0, 1, 2}}}, map[int]int{2: 5}, 1, mask(2, 3, 4), false},
+		{"thermo decreasing", Variant{Thermos: [][]int{{0, 1, 2}}}, map[int]int{0: 5, 1: 4}, 2, mask(7, 8, 9), true},
+		{"thermo bulb too large", Variant{Thermos: [][]int{{0, 1, 2}}}, map[int]int{0: 8}, 1, 0, true},
+		{"arrow circle", Variant{Arrows: []Arrow{{0, []int{1, 2}}}}, nil, 0, mask(2, 3, 4, 5, 6, 7, 8, 9), false},
+		{"arrow", Variant{Arrows: []Arrow{{0, []int{1, 2}}}}, map[int]int{0: 5}, 1, mask(1, 2, 3, 4), false},
+		{"arrow adds up", Variant{Arrows: []Arrow{{0, []int{1, 2}}}}, map[int]int{0: 5, 1: 2, 2: 3}, 3, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), false},
+		{"arrow does not add up", Variant{Arrows: []Arrow{{0, []int{1, 2}}}}, map[int]int{0: 5, 1: 2, 2: 2}, 3, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), true},
+		{"arrow too large", Variant{Arrows: []Arrow{{0, []int{1, 2}}}}, map[int]int{1: 5, 2: 5}, 0, 0, true},
+		{"palindrome", Variant{Palindromes: [][]int{{0, 1, 2}}}, map[int]int{2: 7}, 0, mask(7), false},
+		{"palindrome middle", Variant{Palindromes: [][]int{{0, 1, 2}}}, map[int]int{0: 7, 2: 7}, 1, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), false},
+		{"palindrome broken", Variant{Palindromes: [][]int{{0, 1, 2}}}, map[int]int{0: 7, 2: 6}, 1, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), true},
+		{"sandwich of 0", Variant{SandwichRows: []int{0, -1, -1, -1, -1, -1, -1, -1, -1}}, map[int]int{0: 1}, 1, mask(9), false},
+		{"sandwich of 35", Variant{SandwichRows: []int{35, -1, -1, -1, -1, -1, -1, -1, -1}}, map[int]int{0: 9}, 8, mask(1), false},
+		{"sandwich column", Variant{SandwichColumns: []int{35, -1, -1, -1, -1, -1, -1, -1, -1}}, map[int]int{72: 1}, 0, mask(9), false},
+		{"sandwich broken", Variant{SandwichRows: []int{0, -1, -1, -1, -1, -1, -1, -1, -1}}, map[int]int{0: 1, 2: 9}, 1, 0, true},
+		{"candidates", Variant{Candidates: append([][]int{{1, 2}}, make([][]int, 80)...)}, nil, 0, mask(1, 2), false},
+		{"not a candidate", Variant{Candidates: append([][]int{{1, 2}}, make([][]int, 80)...)}, map[int]int{0: 3}, 1, mask(1, 2, 3, 4, 5, 6, 7, 8, 9), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			board := make(Board, 81)
+			for pos, val := range test.cells {
+				board[pos] = val
+			}
+			allowed, err := checkConstraints(t, test.variant, board, test.pos)
+			if allowed != test.allowed {
+				t.Errorf("got candidates %b, want %b", allowed, test.allowed)
+			}
+			if (err != nil) != test.broken {
+				t.Errorf("got %v, want broken %t", err, test.broken)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package sudoku
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parses a board from 9 rows of 9 comma separated cells, where empty cells are
+// blank or 0, ie. as exported from a spreadsheet. Returns an error if the
+// board is not valid (wrapping ErrInvalidBoard).
+func ParseCSV(data []byte) (Board, error) {
+	board, err := parseCSV(data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = board.IsValid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	return board, nil
+}
+
+func parseCSV(data []byte) (Board, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 9
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) != 9 {
+		error := fmt.Sprintf("Expected 9 rows, got %d", len(records))
+		return nil, errors.New(error)
+	}
+
+	board := make(Board, 0, 81)
+	for y, record := range records {
+		for x, field := range record {
+			field = strings.TrimSpace(field)
+			if field == "" || field == "." {
+				board = append(board, 0)
+				continue
+			}
+			val, err := strconv.Atoi(field)
+			if err != nil {
+				error := fmt.Sprintf("Unexpected cell %q in row %d, column %d", field, y+1, x+1)
+				return nil, errors.New(error)
+			}
+			board = append(board, val)
+		}
+	}
+	return board, nil
+}
+
+// Returns the board as 9 rows of 9 comma separated cells, with empty cells
+// left blank.
+func (b Board) CSV() string {
+	buffer := bytes.NewBufferString("")
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			if x > 0 {
+				buffer.WriteString(",")
+			}
+			if val := b[y*9+x]; val != 0 {
+				buffer.WriteString(strconv.Itoa(val))
+			}
+		}
+		if y < 8 {
+			buffer.WriteString("\n")
+		}
+	}
+	return buffer.String()
+}
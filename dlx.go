@@ -0,0 +1,219 @@
+package sudoku
+
+import (
+	"context"
+)
+
+// The exact cover matrix of a board, as a toroidal doubly linked list of the
+// ones, with a header node per column. Node 0 is the root header, nodes
+// 1-324 are the column headers and the rest are the ones of the matrix.
+//
+// For a 9x9 board, the 729 rows are the candidates (cell, value), and the
+// 324 columns are the constraints: each cell has a value, and each row,
+// column and box has each value once. The extra units of a variant add a
+// column per value, which is only in the header ring (must be covered) if
+// the unit has as many cells as a row.
+type dlx struct {
+	left, right, up, down, column []int
+	// The number of ones in each column.
+	size []int
+	// The candidate (cell*size + value-1) of each node.
+	candidate []int
+	// The size of the board.
+	values int
+	// The board being solved.
+	board Board
+	// Called with each change to the board, if set.
+	observe func(Event)
+	// Counts the work done, if set, and the values tried at the moment.
+	stats *Stats
+	depth int
+}
+
+// Returns the exact cover matrix of a board, which must be valid.
+func newDLX(s *shape, b Board) *dlx {
+	units := make([][]int, len(b))
+	for cell := range units {
+		units[cell] = []int{s.unit(cell, 0), s.unit(cell, 1), s.unit(cell, 2)}
+		for _, extra := range s.extraUnits[cell] {
+			units[cell] = append(units[cell], 3*s.size+extra)
+		}
+	}
+	return newCover(b, s.size, units, len(s.unitCells), func(unit int) bool {
+		return len(s.unitCells[unit]) == s.size
+	})
+}
+
+// Returns the exact cover matrix of a board with the given number of values,
+// where each cell is in the units listed for it. Each value must appear once
+// in the primary units, and at most once in the others. The board must be
+// valid.
+func newCover(b Board, values int, units [][]int, unitCount int, primary func(unit int) bool) *dlx {
+	cells := len(b)
+	dlxColumns := cells + unitCount*values
+	nodes := 1 + dlxColumns
+	for _, cellUnits := range units {
+		nodes += (1 + len(cellUnits)) * values
+	}
+	d := &dlx{
+		left:      make([]int, 0, nodes),
+		right:     make([]int, 0, nodes),
+		up:        make([]int, 0, nodes),
+		down:      make([]int, 0, nodes),
+		column:    make([]int, 0, nodes),
+		size:      make([]int, 1+dlxColumns),
+		candidate: make([]int, 0, nodes),
+		board:     b.deepcopy(b),
+		values:    values,
+	}
+
+	// The root and the column headers, the ones that must be covered
+	// linked in a ring.
+	for i := 0; i <= dlxColumns; i++ {
+		d.left = append(d.left, i)
+		d.right = append(d.right, i)
+		d.up = append(d.up, i)
+		d.down = append(d.down, i)
+		d.column = append(d.column, i)
+		d.candidate = append(d.candidate, -1)
+	}
+	for i := 1; i <= dlxColumns; i++ {
+		if i > cells && !primary((i-1-cells)/values) {
+			continue
+		}
+		d.left[i] = d.left[0]
+		d.right[i] = 0
+		d.right[d.left[0]] = i
+		d.left[0] = i
+	}
+
+	// The rows, one per candidate.
+	rows := make([]int, cells*values)
+	for cell := 0; cell < cells; cell++ {
+		for v := 0; v < values; v++ {
+			first := len(d.column)
+			rows[cell*values+v] = first
+			columns := []int{cell}
+			for _, unit := range units[cell] {
+				columns = append(columns, cells+unit*values+v)
+			}
+			for i, col := range columns {
+				node := first + i
+				d.left = append(d.left, first+(i+len(columns)-1)%len(columns))
+				d.right = append(d.right, first+(i+1)%len(columns))
+				d.up = append(d.up, d.up[col+1])
+				d.down = append(d.down, col+1)
+				d.column = append(d.column, col+1)
+				d.candidate = append(d.candidate, cell*values+v)
+				d.down[d.up[col+1]] = node
+				d.up[col+1] = node
+				d.size[col+1]++
+			}
+		}
+	}
+
+	// Select the rows of the givens.
+	for cell, val := range b {
+		if val == 0 {
+			continue
+		}
+		row := rows[cell*values+val-1]
+		d.cover(d.column[row])
+		for j := d.right[row]; j != row; j = d.right[j] {
+			d.cover(d.column[j])
+		}
+	}
+
+	return d
+}
+
+// Removes a column and every row with a one in it from the matrix.
+func (d *dlx) cover(c int) {
+	d.right[d.left[c]] = d.right[c]
+	d.left[d.right[c]] = d.left[c]
+	for i := d.down[c]; i != c; i = d.down[i] {
+		for j := d.right[i]; j != i; j = d.right[j] {
+			d.down[d.up[j]] = d.down[j]
+			d.up[d.down[j]] = d.up[j]
+			d.size[d.column[j]]--
+		}
+	}
+}
+
+// Restores a column removed by cover.
+func (d *dlx) uncover(c int) {
+	for i := d.up[c]; i != c; i = d.up[i] {
+		for j := d.left[i]; j != i; j = d.left[j] {
+			d.size[d.column[j]]++
+			d.down[d.up[j]] = j
+			d.up[d.down[j]] = j
+		}
+	}
+	d.right[d.left[c]] = c
+	d.left[d.right[c]] = c
+}
+
+// Searches for solutions, calling visit with each solution found. The board
+// passed to visit is only valid during the call. Returns true if visit asked
+// to stop the search.
+func (d *dlx) search(ctx context.Context, visit func(Board) bool) (bool, error) {
+	// Stop searching when the caller is no longer interested.
+	if err := ctx.Err(); err != nil {
+		return true, err
+	}
+	if d.stats != nil {
+		d.stats.enter(d.depth)
+	}
+
+	// Every constraint is satisfied.
+	if d.right[0] == 0 {
+		return visit(d.board), nil
+	}
+
+	// Choose the constraint with the fewest candidates.
+	c := d.right[0]
+	for j := d.right[c]; j != 0; j = d.right[j] {
+		if d.size[j] < d.size[c] {
+			c = j
+		}
+	}
+	if d.size[c] == 0 {
+		return false, nil
+	}
+
+	d.cover(c)
+	defer d.uncover(c)
+	if d.stats != nil {
+		d.stats.Propagations++
+	}
+	for r := d.down[c]; r != c; r = d.down[r] {
+		cell, val := d.candidate[r]/d.values, d.candidate[r]%d.values+1
+		d.board[cell] = val
+		if d.observe != nil {
+			d.observe(Event{Place, cell, val})
+		}
+		for j := d.right[r]; j != r; j = d.right[j] {
+			d.cover(d.column[j])
+			if d.stats != nil {
+				d.stats.Propagations++
+			}
+		}
+		d.depth++
+		stop, err := d.search(ctx, visit)
+		d.depth--
+		for j := d.left[r]; j != r; j = d.left[j] {
+			d.uncover(d.column[j])
+		}
+		if d.observe != nil {
+			d.observe(Event{Remove, cell, val})
+		}
+		d.board[cell] = 0
+		if stop || err != nil {
+			return stop, err
+		}
+		if d.stats != nil {
+			d.stats.Backtracks++
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,127 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// A puzzle in the json format of f-puzzles, of which only the grid and the
+// solution are used.
+type fpuzzle struct {
+	Size     int             `json:"size"`
+	Title    string          `json:"title,omitempty"`
+	Author   string          `json:"author,omitempty"`
+	Grid     [][]fpuzzleCell `json:"grid"`
+	Solution []int           `json:"solution,omitempty"`
+}
+
+type fpuzzleCell struct {
+	Value int  `json:"value,omitempty"`
+	Given bool `json:"given,omitempty"`
+}
+
+// Parses the givens of a puzzle shared as an f-puzzles link, ie.
+// "https://www.f-puzzles.com/?load=N4IgzglgXgpiBcBOANC...", or as a SudokuPad
+// link to an f-puzzles puzzle, ie. "https://sudokupad.app/fpuzzlesN4Ig...".
+// Returns an error if the link cannot be decoded, or the board is not valid
+// (wrapping ErrInvalidBoard). Puzzles in SudokuPad's own format are not
+// supported.
+func ParseURL(link string) (Board, error) {
+	board, err := parseURL(link)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = board.IsValid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	return board, nil
+}
+
+func parseURL(link string) (Board, error) {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return nil, err
+	}
+
+	// The puzzle is in the load or puzzle parameter, or else the path from
+	// "fpuzzles" on (as the encoding includes '/') or its last part. The raw
+	// query is used as '+' is part of the encoding.
+	var data string
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		if strings.HasPrefix(param, "load=") || strings.HasPrefix(param, "puzzle=") {
+			data = param[strings.IndexByte(param, '=')+1:]
+		}
+	}
+	if path := u.EscapedPath(); data == "" {
+		if i := strings.Index(path, "fpuzzles"); i >= 0 {
+			data = path[i:]
+		} else {
+			data = path[strings.LastIndexByte(path, '/')+1:]
+		}
+	}
+	data, err = url.PathUnescape(data)
+	if err != nil {
+		return nil, err
+	}
+	data = strings.Replace(data, " ", "+", -1)
+
+	switch {
+	case strings.HasPrefix(data, "fpuzzles"):
+		data = data[len("fpuzzles"):]
+	case strings.HasPrefix(data, "scl"), strings.HasPrefix(data, "ctc"):
+		return nil, errors.New("Puzzles in SudokuPad's own format are not supported, share it as an f-puzzles link")
+	case data == "":
+		return nil, errors.New("Link does not contain a puzzle")
+	}
+
+	decompressed, err := lzDecompressBase64(data)
+	if err != nil {
+		return nil, err
+	}
+	puzzle := fpuzzle{}
+	if err := json.Unmarshal([]byte(decompressed), &puzzle); err != nil {
+		return nil, err
+	}
+	if puzzle.Size != 9 || len(puzzle.Grid) != 9 {
+		error := fmt.Sprintf("Puzzle is %dx%d, expected 9x9", puzzle.Size, puzzle.Size)
+		return nil, errors.New(error)
+	}
+
+	board := make(Board, 0, 81)
+	for y, row := range puzzle.Grid {
+		if len(row) != 9 {
+			error := fmt.Sprintf("Row %d does not have 9 cells", y+1)
+			return nil, errors.New(error)
+		}
+		for _, cell := range row {
+			if cell.Given {
+				board = append(board, cell.Value)
+			} else {
+				board = append(board, 0)
+			}
+		}
+	}
+	return board, nil
+}
+
+// Returns an f-puzzles link to the board with its filled cells as givens,
+// and the solution if not nil, so the puzzle can be opened in f-puzzles and
+// tools that import from it, like SudokuPad.
+func (b Board) FPuzzlesURL(solution Board) string {
+	puzzle := fpuzzle{Size: 9, Grid: make([][]fpuzzleCell, 9), Solution: solution}
+	for y := range puzzle.Grid {
+		puzzle.Grid[y] = make([]fpuzzleCell, 9)
+		for x := range puzzle.Grid[y] {
+			if val := b[y*9+x]; val != 0 {
+				puzzle.Grid[y][x] = fpuzzleCell{Value: val, Given: true}
+			}
+		}
+	}
+	data, _ := json.Marshal(puzzle)
+	return "https://www.f-puzzles.com/?load=" + lzCompressBase64(string(data))
+}
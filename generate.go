@@ -0,0 +1,360 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Returned when no puzzle of the difficulty asked for is found in
+// maxGenerateAttempts boards, ie. as the rules of the variant rarely or never
+// need the techniques of the difficulty.
+var ErrDifficultyNotReached = errors.New("No puzzle of the difficulty was found")
+
+// The solved boards a puzzle of the difficulty asked for is looked for in.
+// Classic hard puzzles are found in a few hundred.
+const maxGenerateAttempts = 10000
+
+// The symmetry of the clue pattern in a generated puzzle.
+type Symmetry int
+
+const (
+	// Clues are removed without regard to their position.
+	SymmetryNone Symmetry = iota
+	// The clue pattern is unchanged by a 180 degree rotation.
+	SymmetryRotational
+	// The clue pattern is unchanged by mirroring it left to right.
+	SymmetryMirror
+)
+
+var symmetryNames = map[Symmetry]string{
+	SymmetryNone:       "none",
+	SymmetryRotational: "rotational",
+	SymmetryMirror:     "mirror",
+}
+
+func (s Symmetry) String() string {
+	if name, ok := symmetryNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Symmetry(%d)", int(s))
+}
+
+// Encodes the symmetry as its name, ie. in json.
+func (s Symmetry) MarshalText() ([]byte, error) {
+	if _, ok := symmetryNames[s]; !ok {
+		return nil, fmt.Errorf("Unknown symmetry: %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// Decodes the symmetry from its name.
+func (s *Symmetry) UnmarshalText(text []byte) error {
+	parsed, err := ParseSymmetry(string(text))
+	if err != nil {
+		return err
+	}
+	*s = parsed
+	return nil
+}
+
+// Returns the symmetry with the given name, ie. "rotational".
+func ParseSymmetry(name string) (Symmetry, error) {
+	for s, n := range symmetryNames {
+		if n == name {
+			return s, nil
+		}
+	}
+	return 0, errors.New("Unknown symmetry: " + name)
+}
+
+// Options for Generate, the zero value generates a puzzle of any difficulty
+// without symmetry from seed 0.
+type GenerateOptions struct {
+	// The same seed always generates the same puzzle.
+	Seed int64 `json:"seed"`
+	// The symmetry of the clue pattern.
+	Symmetry Symmetry `json:"symmetry"`
+	// The difficulty of the puzzle as rated by Rate, 0 for any. 4x4 puzzles
+	// are always easy, and 6x6 puzzles are never hard, a medium one is
+	// generated instead.
+	Difficulty Difficulty `json:"difficulty,omitempty"`
+	// The number of rows of the board, ie. 6 or 16, 0 for 9.
+	Size int `json:"size,omitempty"`
+	// The rules of the sudoku variant the puzzle is for.
+	Variant Variant `json:"variant"`
+}
+
+// The largest number of rows of a generated puzzle, checking larger puzzles
+// for a unique solution takes too long.
+const maxGenerateSize = 16
+
+// Returns an error if Generate does not support the options, ie. the size
+// of the board or the variant's rules for it, wrapping ErrUnsolvable if no
+// board satisfies the rules.
+func (o GenerateOptions) Validate() error {
+	_, err := o.shape()
+	return err
+}
+
+// Returns the shape of the solutions of the generated puzzles, without the
+// kropki dots placed from them.
+func (o GenerateOptions) shape() (*shape, error) {
+	size := o.Size
+	if size == 0 {
+		size = 9
+	}
+	s, ok := shapeOf(size * size)
+	if !ok || size > maxGenerateSize {
+		error := fmt.Sprintf("Unsupported board size: %d", size)
+		return nil, errors.New(error)
+	}
+	v := o.Variant
+	if v.hasClues() {
+		return nil, errors.New("Puzzles with clues of their own, ie. cages or thermometers, cannot be generated")
+	}
+	v.AllDots = false
+	s, err := v.shape(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filling a board would search forever when the units combined cannot
+	// be satisfied, dancing links finds out quickly.
+	found := false
+	newDLX(s, make(Board, size*size)).search(context.Background(), func(Board) bool {
+		found = true
+		return true
+	})
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnsolvable, "No board satisfies the rules of the variant")
+	}
+	return s, nil
+}
+
+// Returns the options generating the puzzle of the day of date, in UTC, at
+// the difficulty (0 for any): the same puzzle everywhere for the same day,
+// with rotational symmetry.
+func DailyOptions(date time.Time, difficulty Difficulty) GenerateOptions {
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "daily %s %d", date.UTC().Format("2006-01-02"), difficulty)
+	return GenerateOptions{Seed: int64(hash.Sum64()), Symmetry: SymmetryRotational, Difficulty: difficulty}
+}
+
+// Generates a puzzle with a unique solution. Panics if the options are not
+// valid (see GenerateOptions.Validate), or with ErrDifficultyNotReached.
+func Generate(options GenerateOptions) Board {
+	return GeneratePuzzle(options).Board
+}
+
+// Generates a puzzle like Generate, along with the rules it is for, the
+// options' variant with the kropki dots of the solution if it has AllDots.
+func GeneratePuzzle(options GenerateOptions) Puzzle {
+	puzzle, err := GeneratePuzzleContext(context.Background(), options)
+	if err != nil {
+		panic(err)
+	}
+	return puzzle
+}
+
+// Like GeneratePuzzle, but returns an error instead of panicking, and gives
+// up and returns the context's error as soon as ctx is cancelled or its
+// deadline is exceeded.
+func GeneratePuzzleContext(ctx context.Context, options GenerateOptions) (Puzzle, error) {
+	s, err := options.shape()
+	if err != nil {
+		return Puzzle{}, err
+	}
+	size := s.size
+	difficulty := reachableDifficulty(size, options.Difficulty)
+
+	rng := rand.New(rand.NewSource(options.Seed))
+	for attempt := 0; attempt < maxGenerateAttempts; attempt++ {
+		// Start from a random solved board.
+		g := emptyGrid(s)
+		if !g.fill(rng, 0) {
+			panic(ErrUnsolvable)
+		}
+		solution := g.board()
+		variant := options.Variant
+		if variant.AllDots {
+			variant.WhiteDots, variant.BlackDots = solution.dots(s)
+		}
+		solver := Solver{Engine: DancingLinks, Variant: variant}
+
+		// Remove clues in random order, as long as the solution stays unique.
+		board := solution.deepcopy(solution)
+		if err := board.removeClues(ctx, solver, rng.Perm(len(board)), options.Symmetry); err != nil {
+			return Puzzle{}, err
+		}
+		if difficulty == 0 {
+			return Puzzle{Board: board, Variant: variant}, nil
+		}
+
+		// Give clues back while the puzzle is too hard, start over if it
+		// ends up too easy. The puzzle has a unique solution, so rating it
+		// only fails when ctx is done.
+		rating, err := board.rate(ctx, variant)
+		if err != nil {
+			return Puzzle{}, err
+		}
+		for _, i := range rng.Perm(len(board)) {
+			if rating.Difficulty <= difficulty {
+				break
+			}
+			if board[i] == 0 {
+				for _, cell := range options.Symmetry.orbit(i, size) {
+					board[cell] = solution[cell]
+				}
+				if rating, err = board.rate(ctx, variant); err != nil {
+					return Puzzle{}, err
+				}
+			}
+		}
+		if rating.Difficulty == difficulty {
+			return Puzzle{Board: board, Variant: variant}, nil
+		}
+	}
+	return Puzzle{}, fmt.Errorf("%w: %s", ErrDifficultyNotReached, difficulty)
+}
+
+// Returns the white and black kropki dots between the adjacent cells of a
+// solved board, white between 1 and 2.
+func (b Board) dots(s *shape) ([][2]int, [][2]int) {
+	white, black := [][2]int{}, [][2]int{}
+	_, pairs := pairUnits(s, sideBySide, "")
+	for _, pair := range pairs {
+		x, y := b[pair[0]], b[pair[1]]
+		switch {
+		case consecutive(x, y):
+			white = append(white, [2]int{pair[0], pair[1]})
+		case double(x, y):
+			black = append(black, [2]int{pair[0], pair[1]})
+		}
+	}
+	return white, black
+}
+
+// The difficulties puzzles on small boards can be generated at, there are
+// too few cells for the harder techniques to be needed. Missing sizes reach
+// every difficulty.
+var smallDifficulties = map[int][]Difficulty{
+	4: {Easy},
+	6: {Easy, Medium, Expert, Extreme},
+}
+
+// Returns the hardest difficulty up to d that puzzles with size rows can be
+// generated at, 0 stays 0 (any).
+func reachableDifficulty(size int, d Difficulty) Difficulty {
+	difficulties, ok := smallDifficulties[size]
+	if !ok || d == 0 {
+		return d
+	}
+	reachable := difficulties[0]
+	for _, candidate := range difficulties {
+		if candidate <= d {
+			reachable = candidate
+		}
+	}
+	return reachable
+}
+
+// Removes every clue that is not needed for the board to have a unique
+// solution, returns an error wrapping ErrInvalidBoard, ErrUnsolvable or
+// ErrNotUnique if the board does not have exactly one solution.
+func (b Board) Minimize() (Board, error) {
+	count, err := fastSolver.CountSolutions(context.Background(), b, 2)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrUnsolvable
+	}
+	if count > 1 {
+		return nil, ErrNotUnique
+	}
+
+	board := b.deepcopy(b)
+	order := make([]int, len(b))
+	for i := range order {
+		order[i] = i
+	}
+	board.removeClues(context.Background(), fastSolver, order, SymmetryNone)
+	return board, nil
+}
+
+// Tries removing the clues in order, keeping a clue only if the solver no
+// longer finds a unique solution without it. Symmetric cells are removed
+// together to keep the pattern. Returns the context's error if ctx is done
+// first.
+func (b Board) removeClues(ctx context.Context, solver Solver, order []int, symmetry Symmetry) error {
+	for _, i := range order {
+		if b[i] == 0 {
+			continue
+		}
+		cells := symmetry.orbit(i, boardSize(len(b)))
+		vals := make([]int, len(cells))
+		for j, cell := range cells {
+			vals[j] = b[cell]
+			b[cell] = 0
+		}
+		count, _ := solver.CountSolutions(ctx, b, 2)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if count != 1 {
+			for j, cell := range cells {
+				b[cell] = vals[j]
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the cells that must be removed together with cell i, on a board
+// with size rows.
+func (s Symmetry) orbit(i int, size int) []int {
+	var partner int
+	switch s {
+	case SymmetryRotational:
+		partner = size*size - 1 - i
+	case SymmetryMirror:
+		partner = (i/size)*size + size - 1 - i%size
+	default:
+		return []int{i}
+	}
+	if partner == i {
+		return []int{i}
+	}
+	return []int{i, partner}
+}
+
+// Fills the empty cells from pos onwards with a random solution, returns
+// false if there is none.
+func (g *grid) fill(rng *rand.Rand, pos int) bool {
+	if pos == len(g.cells) {
+		return true
+	}
+	if g.cells[pos] != 0 {
+		return g.fill(rng, pos+1)
+	}
+
+	candidates := g.candidates(pos)
+	if g.constraints != nil {
+		candidates = g.allowed(pos, candidates)
+	}
+	for _, i := range rng.Perm(g.size) {
+		if candidates&(1<<uint(i+1)) == 0 {
+			continue
+		}
+		g.set(pos, i+1)
+		if g.fill(rng, pos+1) {
+			return true
+		}
+		g.unset(pos)
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGeneratePuzzleContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		options GenerateOptions
+		invalid bool
+	}{
+		{"classic", GenerateOptions{Seed: 1}, false},
+		{"easy", GenerateOptions{Seed: 2, Difficulty: Easy}, false},
+		{"symmetric", GenerateOptions{Seed: 3, Symmetry: SymmetryRotational}, false},
+		{"4x4", GenerateOptions{Seed: 4, Size: 4}, false},
+		{"6x6 sudoku x", GenerateOptions{Seed: 5, Size: 6, Variant: Variant{Diagonals: true}}, false},
+		{"kropki", GenerateOptions{Seed: 6, Variant: Variant{AllDots: true}}, false},
+		{"bad size", GenerateOptions{Size: 7}, true},
+		{"too large", GenerateOptions{Size: 25}, true},
+		{"cages", GenerateOptions{Variant: Variant{Cages: []Cage{{Cells: []int{0, 1}, Sum: 3}}}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			puzzle, err := GeneratePuzzleContext(context.Background(), test.options)
+			if test.invalid {
+				if err == nil {
+					t.Error("got no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			solver := Solver{Variant: puzzle.Variant}
+			if count, err := solver.CountSolutions(context.Background(), puzzle.Board, 2); count != 1 || err != nil {
+				t.Errorf("got %d solutions, %v, want 1", count, err)
+			}
+			if test.options.Difficulty != 0 {
+				rating, err := puzzle.Board.Rate()
+				if err != nil || rating.Difficulty != test.options.Difficulty {
+					t.Errorf("got difficulty %s, %v, want %s", rating.Difficulty, err, test.options.Difficulty)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePuzzleContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := GeneratePuzzleContext(ctx, GenerateOptions{Seed: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,104 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A value placed in a cell of a board, 0 to clear it.
+type Move struct {
+	Cell  int `json:"cell"`
+	Value int `json:"value"`
+}
+
+// The moves made on a board, ie. by a player, which can be undone and
+// redone. Moves made together, ie. a filled in solution, are undone
+// together.
+type History struct {
+	board Board
+	// The steps made and undone, the last one first to be undone or redone.
+	done, undone []step
+}
+
+// Moves made together, with the values of their cells before them.
+type step struct {
+	moves    []Move
+	previous []int
+}
+
+// Returns a history of the moves made on a copy of b.
+func NewHistory(b Board) *History {
+	board := make(Board, len(b))
+	copy(board, b)
+	return &History{board: board}
+}
+
+// Returns a copy of the board with the moves made.
+func (h *History) Board() Board {
+	board := make(Board, len(h.board))
+	copy(board, h.board)
+	return board
+}
+
+// Makes the moves, together, and forgets the moves undone. Returns an error
+// and makes none of them if a move is not a cell and value of the board.
+func (h *History) Play(moves ...Move) error {
+	size := boardSize(len(h.board))
+	for _, move := range moves {
+		if move.Cell < 0 || move.Cell >= len(h.board) {
+			error := fmt.Sprintf("Move cell %d is not on the board", move.Cell)
+			return errors.New(error)
+		}
+		if move.Value < 0 || move.Value > size {
+			error := fmt.Sprintf("Move value %d is not a value of the board", move.Value)
+			return errors.New(error)
+		}
+	}
+	h.done = append(h.done, h.apply(moves))
+	h.undone = nil
+	return nil
+}
+
+// Makes the moves, returns the step to undo them.
+func (h *History) apply(moves []Move) step {
+	previous := make([]int, len(moves))
+	for i, move := range moves {
+		previous[i] = h.board[move.Cell]
+		h.board[move.Cell] = move.Value
+	}
+	return step{moves, previous}
+}
+
+// Undoes the last moves made, returns them or false if there are none.
+func (h *History) Undo() ([]Move, bool) {
+	if len(h.done) == 0 {
+		return nil, false
+	}
+	last := h.done[len(h.done)-1]
+	h.done = h.done[:len(h.done)-1]
+	for i := len(last.moves) - 1; i >= 0; i-- {
+		h.board[last.moves[i].Cell] = last.previous[i]
+	}
+	h.undone = append(h.undone, last)
+	return last.moves, true
+}
+
+// Redoes the last moves undone, returns them or false if there are none.
+func (h *History) Redo() ([]Move, bool) {
+	if len(h.undone) == 0 {
+		return nil, false
+	}
+	last := h.undone[len(h.undone)-1]
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, h.apply(last.moves))
+	return last.moves, true
+}
+
+// Returns the moves made and not undone, in order.
+func (h *History) Moves() []Move {
+	moves := []Move{}
+	for _, step := range h.done {
+		moves = append(moves, step.moves...)
+	}
+	return moves
+}
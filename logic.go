@@ -0,0 +1,662 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+var (
+	// Returned by the logical solver when none of its techniques apply.
+	ErrStuck = errors.New("Board cannot be solved without guessing")
+	// Returned when asking for a hint on a board without empty cells.
+	ErrSolved = errors.New("Board is already solved")
+)
+
+// A candidate value for a cell.
+type Candidate struct {
+	Cell  int `json:"cell"`
+	Value int `json:"value"`
+}
+
+// A single deduction made by the logical solver. A step either places a
+// value in a cell or eliminates candidates from cells.
+type Step struct {
+	// The name of the technique, ie. "hidden single".
+	Technique string `json:"technique"`
+	// The cell being solved and its value, Cell is -1 and Value is 0 if the
+	// step only eliminates candidates.
+	Cell  int `json:"cell"`
+	Value int `json:"value"`
+	// The candidates removed by the step.
+	Eliminations []Candidate `json:"eliminations,omitempty"`
+	// The cells that justify the deduction.
+	Cells []int `json:"cells"`
+}
+
+// A human solving technique, apply returns the first deduction the technique
+// can make on the grid or nil.
+type technique struct {
+	name   string
+	level  Difficulty
+	weight int
+	apply  func(g *logicGrid) *Step
+}
+
+// The techniques, easiest first.
+var techniques = []technique{
+	{"hidden single", Easy, 1, hiddenSingle},
+	{"naked single", Easy, 2, nakedSingle},
+	{"variant rule", Easy, 3, variantRule},
+	{"pointing", Medium, 4, pointing},
+	{"box/line reduction", Medium, 5, boxLineReduction},
+	{"naked pair", Medium, 6, nakedSubset(2)},
+	{"hidden pair", Medium, 8, hiddenSubset(2)},
+	{"naked triple", Medium, 10, nakedSubset(3)},
+	{"hidden triple", Medium, 12, hiddenSubset(3)},
+	{"x-wing", Hard, 20, fish(2)},
+	{"swordfish", Hard, 30, fish(3)},
+	{"xy-wing", Expert, 40, xyChain(3, 3)},
+	{"xy-chain", Expert, 50, xyChain(4, 12)},
+}
+
+// A board with the remaining candidates (bit v set for value v) of each cell.
+type logicGrid struct {
+	*shape
+	board      Board
+	candidates []uint32
+}
+
+// Returns the grid of a board of the given shape, which must be valid.
+func newLogicGrid(s *shape, b Board) *logicGrid {
+	g := &logicGrid{shape: s, board: make(Board, len(b)), candidates: make([]uint32, len(b))}
+	for cell := range g.candidates {
+		g.candidates[cell] = (1<<uint(s.size) - 1) << 1
+	}
+	for cell, val := range b {
+		if val != 0 {
+			g.place(cell, val)
+		}
+	}
+	return g
+}
+
+// Places val in cell and removes it as a candidate from the peers.
+func (g *logicGrid) place(cell int, val int) {
+	g.board[cell] = val
+	g.candidates[cell] = 0
+	for _, peer := range g.peers[cell] {
+		g.candidates[peer] &^= 1 << uint(val)
+	}
+}
+
+// Applies the deduction of a step to the grid.
+func (g *logicGrid) apply(s *Step) {
+	if s.Cell >= 0 {
+		g.place(s.Cell, s.Value)
+	}
+	for _, e := range s.Eliminations {
+		g.candidates[e.Cell] &^= 1 << uint(e.Value)
+	}
+}
+
+// Returns true when every cell has a value.
+func (g *logicGrid) solved() bool {
+	for _, val := range g.board {
+		if val == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the first applicable step of the easiest technique that applies,
+// along with the technique, or nil if no technique applies.
+func (g *logicGrid) next() (*Step, *technique) {
+	for i := range techniques {
+		if s := techniques[i].apply(g); s != nil {
+			s.Technique = techniques[i].name
+			return s, &techniques[i]
+		}
+	}
+	return nil, nil
+}
+
+// Solves the board step by step with human techniques (singles, subsets,
+// intersections, fish and chains), easiest first, instead of backtracking.
+// Returns the ordered deductions, or the deductions made so far and
+// ErrStuck if no technique applies before the board is solved. Returns an
+// error wrapping ErrInvalidBoard if the board is not valid.
+func (b Board) SolveLogical() ([]Step, error) {
+	_, err := b.IsValid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+
+	steps := []Step{}
+	s, _ := b.shape()
+	g := newLogicGrid(s, b)
+	for !g.solved() {
+		s, _ := g.next()
+		if s == nil {
+			return steps, ErrStuck
+		}
+		g.apply(s)
+		steps = append(steps, *s)
+	}
+	return steps, nil
+}
+
+// Returns the next value that can be placed with human techniques. If
+// candidates must be eliminated before a value can be placed, the hint
+// includes those eliminations and their cells, and names the hardest
+// technique used. Returns ErrSolved if there are no empty cells, ErrStuck if
+// no technique applies and an error wrapping ErrInvalidBoard or
+// ErrUnsolvable if the board cannot be solved.
+func (b Board) Hint() (Step, error) {
+	return b.HintContext(context.Background())
+}
+
+// Like Hint, but gives up and returns the context's error as soon as ctx is
+// cancelled or its deadline is exceeded.
+func (b Board) HintContext(ctx context.Context) (Step, error) {
+	_, err := fastSolver.Solve(ctx, b)
+	if err != nil {
+		return Step{}, err
+	}
+
+	s, _ := b.shape()
+	g := newLogicGrid(s, b)
+	if g.solved() {
+		return Step{}, ErrSolved
+	}
+
+	hint := Step{}
+	hardest := -1
+	seen := map[int]bool{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return Step{}, err
+		}
+		s, t := g.next()
+		if s == nil {
+			return Step{}, ErrStuck
+		}
+		g.apply(s)
+
+		if t.weight > hardest {
+			hardest = t.weight
+			hint.Technique = t.name
+		}
+		hint.Eliminations = append(hint.Eliminations, s.Eliminations...)
+		for _, c := range s.Cells {
+			if !seen[c] {
+				seen[c] = true
+				hint.Cells = append(hint.Cells, c)
+			}
+		}
+		if s.Cell >= 0 {
+			hint.Cell = s.Cell
+			hint.Value = s.Value
+			return hint, nil
+		}
+	}
+}
+
+// Returns the candidates of each cell after basic propagation, the values
+// not used by any of its peers, and the value of filled cells as their only
+// candidate. Returns an error wrapping ErrInvalidBoard if the board is not
+// valid.
+func (b Board) Candidates() ([][]int, error) {
+	if _, err := b.IsValid(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	s, _ := b.shape()
+	g := newLogicGrid(s, b)
+	candidates := make([][]int, len(b))
+	for cell, mask := range g.candidates {
+		if b[cell] != 0 {
+			mask = 1 << uint(b[cell])
+		}
+		candidates[cell] = maskValues(mask)
+	}
+	return candidates, nil
+}
+
+// Returns the candidates of each cell like Candidates, as a grid like
+// String with each cell written as its candidates, padded to the same
+// width, or '.' if it has none.
+func (b Board) CandidatesString() (string, error) {
+	candidates, err := b.Candidates()
+	if err != nil {
+		return "", err
+	}
+	s, _ := b.shape()
+	texts := make([]string, len(b))
+	width := 1
+	for cell, values := range candidates {
+		text := []byte{}
+		for _, val := range values {
+			text = append(text, valueChar(val))
+		}
+		if len(text) == 0 {
+			text = []byte(".")
+		}
+		texts[cell] = string(text)
+		width = max(width, len(text))
+	}
+
+	box := strings.Repeat("-", s.boxColumns*(width+1)-1)
+	separator := strings.Repeat(box+"-+-", s.size/s.boxColumns)
+	separator = separator[:len(separator)-3]
+	lines := []string{}
+	for y := 0; y < s.size; y++ {
+		if y > 0 && y%s.boxRows == 0 {
+			lines = append(lines, separator)
+		}
+		line := ""
+		for x := 0; x < s.size; x++ {
+			if x > 0 && x%s.boxColumns == 0 {
+				line += " | "
+			} else if x > 0 {
+				line += " "
+			}
+			line += fmt.Sprintf("%-*s", width, texts[s.cell(y, x)])
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// Returns the values of a candidate mask in increasing order.
+func maskValues(mask uint32) []int {
+	values := make([]int, 0, bits.OnesCount32(mask))
+	for ; mask != 0; mask &= mask - 1 {
+		values = append(values, bits.TrailingZeros32(mask))
+	}
+	return values
+}
+
+// Returns true if the two cells share a unit.
+func (s *shape) sees(a int, b int) bool {
+	ua, ub := s.units[a], s.units[b]
+	return a != b && (ua[0] == ub[0] || ua[1] == ub[1] || ua[2] == ub[2])
+}
+
+// Calls fn with every combination of n elements of set, stops when fn
+// returns true.
+func combinations(set []int, n int, fn func([]int) bool) bool {
+	combo := make([]int, n)
+	var rec func(start int, depth int) bool
+	rec = func(start int, depth int) bool {
+		if depth == n {
+			return fn(combo)
+		}
+		for i := start; i <= len(set)-(n-depth); i++ {
+			combo[depth] = set[i]
+			if rec(i+1, depth+1) {
+				return true
+			}
+		}
+		return false
+	}
+	return rec(0, 0)
+}
+
+// A value that can only go in one cell of a unit.
+func hiddenSingle(g *logicGrid) *Step {
+	for _, unit := range g.unitCells {
+		// Smaller units, ie. cages, do not have every value.
+		if len(unit) < g.size {
+			continue
+		}
+		for v := 1; v <= g.size; v++ {
+			cell, count := -1, 0
+			for _, c := range unit {
+				if g.candidates[c]&(1<<uint(v)) != 0 {
+					cell = c
+					count++
+				}
+			}
+			if count == 1 {
+				return &Step{Cell: cell, Value: v, Cells: append([]int(nil), unit...)}
+			}
+		}
+	}
+	return nil
+}
+
+// A cell with only one candidate left.
+func nakedSingle(g *logicGrid) *Step {
+	for cell, mask := range g.candidates {
+		if bits.OnesCount32(mask) != 1 {
+			continue
+		}
+		s := &Step{Cell: cell, Value: bits.TrailingZeros32(mask)}
+		for _, peer := range g.peers[cell] {
+			if g.board[peer] != 0 {
+				s.Cells = append(s.Cells, peer)
+			}
+		}
+		return s
+	}
+	return nil
+}
+
+// Candidates of a cell that the other rules of a variant rule out with the
+// values placed, ie. the values consecutive to a neighbour's in
+// non-consecutive sudoku.
+func variantRule(g *logicGrid) *Step {
+	for cell, mask := range g.candidates {
+		for _, c := range g.cellConstraints[cell] {
+			removed := mask &^ g.constraints[c].allowed(g, cell)
+			if removed == 0 {
+				continue
+			}
+			s := &Step{Cell: -1, Cells: append([]int(nil), g.constraints[c].cells()...)}
+			for _, v := range maskValues(removed) {
+				s.Eliminations = append(s.Eliminations, Candidate{cell, v})
+			}
+			return s
+		}
+	}
+	return nil
+}
+
+// Removes the value from every cell in unit that is not in keep, returns the
+// eliminations.
+func eliminate(g *logicGrid, unit []int, value int, keep func(cell int) bool) []Candidate {
+	var result []Candidate
+	for _, c := range unit {
+		if g.candidates[c]&(1<<uint(value)) != 0 && !keep(c) {
+			result = append(result, Candidate{c, value})
+		}
+	}
+	return result
+}
+
+// The cells of unit that have value as a candidate.
+func cellsWith(g *logicGrid, unit []int, value int) []int {
+	var result []int
+	for _, c := range unit {
+		if g.candidates[c]&(1<<uint(value)) != 0 {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// A value confined to one row or column within a box, which removes it from
+// the rest of that row or column.
+func pointing(g *logicGrid) *Step {
+	for b := 2 * g.size; b < 3*g.size; b++ {
+		for v := 1; v <= g.size; v++ {
+			cells := cellsWith(g, g.unitCells[b], v)
+			if len(cells) < 2 {
+				continue
+			}
+			for _, kind := range []int{0, 1} {
+				line := g.unit(cells[0], kind)
+				aligned := true
+				for _, c := range cells {
+					aligned = aligned && g.unit(c, kind) == line
+				}
+				if !aligned {
+					continue
+				}
+				eliminations := eliminate(g, g.unitCells[line], v, func(c int) bool {
+					return g.unit(c, 2) == b
+				})
+				if len(eliminations) > 0 {
+					return &Step{Cell: -1, Eliminations: eliminations, Cells: cells}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// A value confined to one box within a row or column, which removes it from
+// the rest of that box.
+func boxLineReduction(g *logicGrid) *Step {
+	for line := 0; line < 2*g.size; line++ {
+		for v := 1; v <= g.size; v++ {
+			cells := cellsWith(g, g.unitCells[line], v)
+			if len(cells) < 2 {
+				continue
+			}
+			box := g.unit(cells[0], 2)
+			aligned := true
+			for _, c := range cells {
+				aligned = aligned && g.unit(c, 2) == box
+			}
+			if !aligned {
+				continue
+			}
+			eliminations := eliminate(g, g.unitCells[box], v, func(c int) bool {
+				return g.unit(c, line/g.size) == line
+			})
+			if len(eliminations) > 0 {
+				return &Step{Cell: -1, Eliminations: eliminations, Cells: cells}
+			}
+		}
+	}
+	return nil
+}
+
+// n cells in a unit with only n candidates between them, which removes those
+// candidates from the rest of the unit.
+func nakedSubset(n int) func(g *logicGrid) *Step {
+	return func(g *logicGrid) *Step {
+		for _, unit := range g.unitCells {
+			var open []int
+			for _, c := range unit {
+				if count := bits.OnesCount32(g.candidates[c]); count >= 2 && count <= n {
+					open = append(open, c)
+				}
+			}
+			var result *Step
+			combinations(open, n, func(cells []int) bool {
+				var mask uint32
+				for _, c := range cells {
+					mask |= g.candidates[c]
+				}
+				if bits.OnesCount32(mask) != n {
+					return false
+				}
+				s := &Step{Cell: -1, Cells: append([]int(nil), cells...)}
+				for _, v := range maskValues(mask) {
+					s.Eliminations = append(s.Eliminations, eliminate(g, unit, v, func(c int) bool {
+						for _, k := range cells {
+							if k == c {
+								return true
+							}
+						}
+						return false
+					})...)
+				}
+				if len(s.Eliminations) > 0 {
+					result = s
+					return true
+				}
+				return false
+			})
+			if result != nil {
+				return result
+			}
+		}
+		return nil
+	}
+}
+
+// n values confined to the same n cells of a unit, which removes every other
+// candidate from those cells.
+func hiddenSubset(n int) func(g *logicGrid) *Step {
+	return func(g *logicGrid) *Step {
+		for _, unit := range g.unitCells {
+			if len(unit) < g.size {
+				continue
+			}
+			var values []int
+			for v := 1; v <= g.size; v++ {
+				if count := len(cellsWith(g, unit, v)); count >= 2 && count <= n {
+					values = append(values, v)
+				}
+			}
+			var result *Step
+			combinations(values, n, func(combo []int) bool {
+				var keep uint32
+				cells := map[int]bool{}
+				for _, v := range combo {
+					keep |= 1 << uint(v)
+					for _, c := range cellsWith(g, unit, v) {
+						cells[c] = true
+					}
+				}
+				if len(cells) != n {
+					return false
+				}
+				s := &Step{Cell: -1}
+				for _, c := range unit {
+					if !cells[c] {
+						continue
+					}
+					s.Cells = append(s.Cells, c)
+					for _, v := range maskValues(g.candidates[c] &^ keep) {
+						s.Eliminations = append(s.Eliminations, Candidate{c, v})
+					}
+				}
+				if len(s.Eliminations) > 0 {
+					result = s
+					return true
+				}
+				return false
+			})
+			if result != nil {
+				return result
+			}
+		}
+		return nil
+	}
+}
+
+// A value confined to the same n columns in n rows (or the other way
+// around), which removes it from the rest of those columns (or rows).
+func fish(n int) func(g *logicGrid) *Step {
+	return func(g *logicGrid) *Step {
+		for v := 1; v <= g.size; v++ {
+			// base 0 uses rows as base lines, base size uses columns.
+			for _, base := range []int{0, g.size} {
+				cover := g.size - base
+				var lines []int
+				for line := base; line < base+g.size; line++ {
+					if count := len(cellsWith(g, g.unitCells[line], v)); count >= 2 && count <= n {
+						lines = append(lines, line)
+					}
+				}
+				var result *Step
+				combinations(lines, n, func(combo []int) bool {
+					covers := map[int]bool{}
+					inBase := map[int]bool{}
+					var cells []int
+					for _, line := range combo {
+						inBase[line] = true
+						for _, c := range cellsWith(g, g.unitCells[line], v) {
+							covers[g.unit(c, cover/g.size)] = true
+							cells = append(cells, c)
+						}
+					}
+					if len(covers) != n {
+						return false
+					}
+					s := &Step{Cell: -1, Cells: cells}
+					for line := cover; line < cover+g.size; line++ {
+						if !covers[line] {
+							continue
+						}
+						s.Eliminations = append(s.Eliminations, eliminate(g, g.unitCells[line], v, func(c int) bool {
+							return inBase[g.unit(c, base/g.size)]
+						})...)
+					}
+					if len(s.Eliminations) > 0 {
+						result = s
+						return true
+					}
+					return false
+				})
+				if result != nil {
+					return result
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// A chain of cells with two candidates each, where each cell sees the next
+// and shares a candidate with it. If the first cell is not z, the chain
+// forces the last cell to be z, so cells seeing both ends cannot be z.
+// Chains between min and max cells long are considered.
+func xyChain(min int, max int) func(g *logicGrid) *Step {
+	return func(g *logicGrid) *Step {
+		var bivalue []int
+		for c, mask := range g.candidates {
+			if bits.OnesCount32(mask) == 2 {
+				bivalue = append(bivalue, c)
+			}
+		}
+
+		var result *Step
+		var chain []int
+		inChain := map[int]bool{}
+		var extend func(z int, out int) bool
+		extend = func(z int, out int) bool {
+			last := chain[len(chain)-1]
+			if len(chain) >= min && out == z {
+				s := &Step{Cell: -1, Cells: append([]int(nil), chain...)}
+				for c := range g.candidates {
+					if !inChain[c] && g.candidates[c]&(1<<uint(z)) != 0 && g.sees(c, chain[0]) && g.sees(c, last) {
+						s.Eliminations = append(s.Eliminations, Candidate{c, z})
+					}
+				}
+				if len(s.Eliminations) > 0 {
+					result = s
+					return true
+				}
+			}
+			if len(chain) == max {
+				return false
+			}
+			for _, c := range bivalue {
+				if inChain[c] || !g.sees(c, last) || g.candidates[c]&(1<<uint(out)) == 0 {
+					continue
+				}
+				chain = append(chain, c)
+				inChain[c] = true
+				found := extend(z, bits.TrailingZeros32(g.candidates[c]&^(1<<uint(out))))
+				chain = chain[:len(chain)-1]
+				delete(inChain, c)
+				if found {
+					return true
+				}
+			}
+			return false
+		}
+
+		for _, start := range bivalue {
+			values := maskValues(g.candidates[start])
+			for i, z := range values {
+				chain = append(chain[:0], start)
+				inChain[start] = true
+				found := extend(z, values[1-i])
+				delete(inChain, start)
+				if found {
+					return result
+				}
+			}
+		}
+		return nil
+	}
+}
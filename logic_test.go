@@ -0,0 +1,217 @@
+package sudoku
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// A board without givens.
+var emptyBoard = strings.Repeat(".", 81)
+
+// The candidates of some cells of a grid.
+type cellCandidates struct {
+	cells []int
+	mask  uint32
+}
+
+// Returns every value from 1 to 9 but the values, as a bitmask of candidates.
+func without(values ...int) uint32 {
+	return mask(1, 2, 3, 4, 5, 6, 7, 8, 9) &^ mask(values...)
+}
+
+// Returns the candidates of each value in each of the cells.
+func each(cells []int, values ...int) []Candidate {
+	result := []Candidate{}
+	for _, c := range cells {
+		for _, v := range values {
+			result = append(result, Candidate{c, v})
+		}
+	}
+	return result
+}
+
+// Returns the candidates ordered by cell, then value.
+func sortCandidates(candidates []Candidate) []Candidate {
+	result := append([]Candidate{}, candidates...)
+	sort.Slice(result, func(i int, j int) bool {
+		if result[i].Cell != result[j].Cell {
+			return result[i].Cell < result[j].Cell
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+func TestTechniques(t *testing.T) {
+	tests := []struct {
+		technique string
+		variant   Variant
+		// The filled cells of an otherwise empty 9x9 board.
+		cells map[int]int
+		// The candidates left in some of the empty cells, the others have
+		// every value left.
+		candidates []cellCandidates
+		// The step found, Cell is -1 if it only eliminates candidates.
+		cell         int
+		value        int
+		eliminations []Candidate
+	}{
+		{"hidden single", Variant{}, nil, []cellCandidates{
+			{[]int{0, 1, 2, 3, 5, 6, 7, 8}, without(5)},
+		}, 4, 5, nil},
+		{"naked single", Variant{}, nil, []cellCandidates{
+			{[]int{10}, mask(7)},
+		}, 10, 7, nil},
+		{"variant rule", Variant{NonConsecutive: true}, map[int]int{0: 5}, nil,
+			-1, 0, each([]int{1}, 4, 6)},
+		{"pointing", Variant{}, nil, []cellCandidates{
+			{[]int{2, 9, 10, 11, 18, 19, 20}, without(3)},
+		}, -1, 0, each([]int{3, 4, 5, 6, 7, 8}, 3)},
+		{"box/line reduction", Variant{}, nil, []cellCandidates{
+			{[]int{2, 3, 4, 5, 6, 7, 8}, without(3)},
+		}, -1, 0, each([]int{9, 10, 11, 18, 19, 20}, 3)},
+		{"naked pair", Variant{}, nil, []cellCandidates{
+			{[]int{0, 1}, mask(1, 2)},
+		}, -1, 0, each([]int{2, 3, 4, 5, 6, 7, 8}, 1, 2)},
+		{"hidden pair", Variant{}, nil, []cellCandidates{
+			{[]int{2, 3, 4, 5, 6, 7, 8}, without(1, 2)},
+		}, -1, 0, each([]int{0, 1}, 3, 4, 5, 6, 7, 8, 9)},
+		{"naked triple", Variant{}, nil, []cellCandidates{
+			{[]int{0}, mask(1, 2)},
+			{[]int{1}, mask(2, 3)},
+			{[]int{2}, mask(1, 3)},
+		}, -1, 0, each([]int{3, 4, 5, 6, 7, 8}, 1, 2, 3)},
+		{"hidden triple", Variant{}, nil, []cellCandidates{
+			{[]int{3, 4, 5, 6, 7, 8}, without(1, 2, 3)},
+		}, -1, 0, each([]int{0, 1, 2}, 4, 5, 6, 7, 8, 9)},
+		{"x-wing", Variant{}, nil, []cellCandidates{
+			{[]int{0, 2, 3, 4, 5, 6, 8, 36, 38, 39, 40, 41, 42, 44}, without(5)},
+		}, -1, 0, each([]int{10, 16, 19, 25, 28, 34, 46, 52, 55, 61, 64, 70, 73, 79}, 5)},
+		{"swordfish", Variant{}, nil, []cellCandidates{
+			{[]int{1, 2, 4, 5, 6, 7, 8, 27, 28, 29, 31, 32, 34, 35, 55, 56, 57, 58, 59, 61, 62}, without(5)},
+		}, -1, 0, each([]int{9, 12, 15, 18, 21, 24, 36, 39, 42, 45, 48, 51, 63, 66, 69, 72, 75, 78}, 5)},
+		{"xy-wing", Variant{}, nil, []cellCandidates{
+			{[]int{0}, mask(1, 2)},
+			{[]int{4}, mask(1, 3)},
+			{[]int{18}, mask(2, 3)},
+		}, -1, 0, each([]int{1, 2, 21, 22, 23}, 3)},
+		{"xy-chain", Variant{}, nil, []cellCandidates{
+			{[]int{0}, mask(1, 2)},
+			{[]int{4}, mask(2, 3)},
+			{[]int{40}, mask(3, 4)},
+			{[]int{36}, mask(4, 1)},
+		}, -1, 0, each([]int{9, 18, 27, 45, 54, 63, 72}, 1)},
+	}
+	for _, test := range tests {
+		t.Run(test.technique, func(t *testing.T) {
+			var technique *technique
+			for i := range techniques {
+				if techniques[i].name == test.technique {
+					technique = &techniques[i]
+				}
+			}
+			if technique == nil {
+				t.Fatalf("no technique named %q", test.technique)
+			}
+			s, _ := shapeOf(81)
+			s, err := test.variant.shape(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			board := make(Board, 81)
+			for pos, val := range test.cells {
+				board[pos] = val
+			}
+			g := newLogicGrid(s, board)
+			for _, c := range test.candidates {
+				for _, cell := range c.cells {
+					g.candidates[cell] &= c.mask
+				}
+			}
+
+			step := technique.apply(g)
+			if step == nil {
+				t.Fatal("got no step")
+			}
+			if step.Cell != test.cell || step.Value != test.value {
+				t.Errorf("got cell %d and value %d, want %d and %d", step.Cell, step.Value, test.cell, test.value)
+			}
+			if got, want := sortCandidates(step.Eliminations), sortCandidates(test.eliminations); !reflect.DeepEqual(got, want) {
+				t.Errorf("got eliminations %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSolveLogical(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		err  error
+	}{
+		{"easy", easyLine, nil},
+		{"solved", easySolution, nil},
+		{"empty", emptyBoard, ErrStuck},
+		{"invalid", "11" + easyLine[2:], ErrInvalidBoard},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			board, err := parseLine(test.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			steps, err := board.SolveLogical()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+			if err != nil {
+				return
+			}
+			solved := append(Board(nil), board...)
+			for _, step := range steps {
+				if step.Cell >= 0 {
+					solved[step.Cell] = step.Value
+				}
+			}
+			if solved.Line() != easySolution {
+				t.Errorf("got %s, want %s", solved.Line(), easySolution)
+			}
+		})
+	}
+}
+
+func TestHint(t *testing.T) {
+	unsolvable := "12345678.........9" + emptyBoard[18:]
+	tests := []struct {
+		name string
+		line string
+		err  error
+	}{
+		{"easy", easyLine, nil},
+		{"solved", easySolution, ErrSolved},
+		{"empty", emptyBoard, ErrStuck},
+		{"invalid", "11" + easyLine[2:], ErrInvalidBoard},
+		{"unsolvable", unsolvable, ErrUnsolvable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			board, err := parseLine(test.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hint, err := board.Hint()
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+			if err != nil {
+				return
+			}
+			if board[hint.Cell] != 0 || easySolution[hint.Cell] != byte('0'+hint.Value) {
+				t.Errorf("got %d in cell %d, which is not the solution", hint.Value, hint.Cell)
+			}
+		})
+	}
+}
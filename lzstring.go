@@ -0,0 +1,218 @@
+package sudoku
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// The alphabet of lz-string's base64 encoding, as used by f-puzzles and
+// SudokuPad links.
+const lzBase64 = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+// Writes the bits of lz-string's compressed stream as base64 characters.
+type lzWriter struct {
+	output   []byte
+	val      int
+	position int
+}
+
+// Writes the n lowest bits of value, lowest first.
+func (w *lzWriter) bits(value int, n int) {
+	for i := 0; i < n; i++ {
+		w.val = w.val<<1 | value&1
+		value >>= 1
+		if w.position == 5 {
+			w.output = append(w.output, lzBase64[w.val])
+			w.position = 0
+			w.val = 0
+		} else {
+			w.position++
+		}
+	}
+}
+
+// Compresses a string like lz-string's compressToBase64.
+func lzCompressBase64(input string) string {
+	w := &lzWriter{}
+	dictionary := map[string]int{}
+	// The single characters added to the dictionary but not written yet.
+	pending := map[string]bool{}
+	enlargeIn := 2
+	numBits := 2
+	size := 3
+
+	// Strings are compressed as UTF-16, like in JavaScript, and keyed by the
+	// bytes of their units.
+	units := utf16.Encode([]rune(input))
+	key := func(units []uint16) string {
+		k := make([]byte, 0, 2*len(units))
+		for _, unit := range units {
+			k = append(k, byte(unit>>8), byte(unit))
+		}
+		return string(k)
+	}
+	enlarge := func() {
+		enlargeIn--
+		if enlargeIn == 0 {
+			enlargeIn = 1 << uint(numBits)
+			numBits++
+		}
+	}
+	emit := func(phrase []uint16) {
+		k := key(phrase)
+		if pending[k] {
+			if phrase[0] < 256 {
+				w.bits(0, numBits)
+				w.bits(int(phrase[0]), 8)
+			} else {
+				w.bits(1, numBits)
+				w.bits(int(phrase[0]), 16)
+			}
+			enlarge()
+			delete(pending, k)
+		} else {
+			w.bits(dictionary[k], numBits)
+		}
+		enlarge()
+	}
+
+	var phrase []uint16
+	for i := range units {
+		c := units[i : i+1]
+		if _, ok := dictionary[key(c)]; !ok {
+			dictionary[key(c)] = size
+			size++
+			pending[key(c)] = true
+		}
+		extended := append(append([]uint16(nil), phrase...), c[0])
+		if _, ok := dictionary[key(extended)]; ok {
+			phrase = extended
+			continue
+		}
+		emit(phrase)
+		dictionary[key(extended)] = size
+		size++
+		phrase = c
+	}
+	if len(phrase) > 0 {
+		emit(phrase)
+	}
+
+	// Mark the end of the stream and flush the last character, which like
+	// lz-string always writes one more.
+	w.bits(2, numBits)
+	for n := len(w.output); len(w.output) == n; {
+		w.bits(0, 1)
+	}
+	for len(w.output)%4 != 0 {
+		w.output = append(w.output, '=')
+	}
+	return string(w.output)
+}
+
+// Reads the bits of lz-string's compressed stream, 6 per base64 character.
+type lzReader struct {
+	input    string
+	index    int
+	val      int
+	position int
+}
+
+func (r *lzReader) bits(n int) (int, error) {
+	result := 0
+	for power := 1; power < 1<<uint(n); power <<= 1 {
+		if r.position == 0 {
+			if r.index >= len(r.input) {
+				return 0, errors.New("Compressed data ends unexpectedly")
+			}
+			r.val = strings.IndexByte(lzBase64, r.input[r.index])
+			if r.val < 0 {
+				return 0, errors.New("Compressed data is not base64")
+			}
+			r.index++
+			r.position = 32
+		}
+		if r.val&r.position != 0 {
+			result |= power
+		}
+		r.position >>= 1
+	}
+	return result, nil
+}
+
+// Decompresses a string compressed with lz-string's compressToBase64.
+func lzDecompressBase64(input string) (string, error) {
+	r := &lzReader{input: input}
+	dictionary := [][]uint16{nil, nil, nil}
+	enlargeIn := 4
+	numBits := 3
+
+	// Reads a literal character of 8 or 16 bits into the dictionary.
+	literal := func(kind int) ([]uint16, error) {
+		size := 8
+		if kind == 1 {
+			size = 16
+		}
+		c, err := r.bits(size)
+		if err != nil {
+			return nil, err
+		}
+		return []uint16{uint16(c)}, nil
+	}
+
+	kind, err := r.bits(2)
+	if err != nil {
+		return "", err
+	}
+	if kind == 2 {
+		return "", nil
+	}
+	w, err := literal(kind)
+	if err != nil {
+		return "", err
+	}
+	dictionary = append(dictionary, w)
+	result := append([]uint16(nil), w...)
+
+	for {
+		c, err := r.bits(numBits)
+		if err != nil {
+			return "", err
+		}
+		switch c {
+		case 0, 1:
+			entry, err := literal(c)
+			if err != nil {
+				return "", err
+			}
+			dictionary = append(dictionary, entry)
+			c = len(dictionary) - 1
+			enlargeIn--
+		case 2:
+			return string(utf16.Decode(result)), nil
+		}
+		if enlargeIn == 0 {
+			enlargeIn = 1 << uint(numBits)
+			numBits++
+		}
+
+		var entry []uint16
+		switch {
+		case c < len(dictionary):
+			entry = dictionary[c]
+		case c == len(dictionary):
+			entry = append(append([]uint16(nil), w...), w[0])
+		default:
+			return "", errors.New("Compressed data is corrupt")
+		}
+		result = append(result, entry...)
+		dictionary = append(dictionary, append(append([]uint16(nil), w...), entry[0]))
+		enlargeIn--
+		w = entry
+		if enlargeIn == 0 {
+			enlargeIn = 1 << uint(numBits)
+			numBits++
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Returns true if val, or 0 to clear the cell, can be placed at column col
+// of row row, counted from 0, without breaking a rule of the board.
+func (b Board) IsLegalMove(row int, col int, val int) bool {
+	_, broken := b.ApplyMove(row, col, val)
+	return len(broken) == 0
+}
+
+// Returns a copy of the board with val placed at column col of row row,
+// counted from 0, and the rules the value then breaks, ie. "Number 5 appears
+// twice in row 3". Returns no board and the error if the board has values
+// out of range, or the move is not a cell and value of the board.
+func (b Board) ApplyMove(row int, col int, val int) (Board, []error) {
+	s, err := b.shape()
+	if err != nil {
+		return nil, []error{err}
+	}
+	return b.applyMove(s, row, col, val)
+}
+
+// Returns true if val can be placed like Board.IsLegalMove, under the
+// variant's rules.
+func (v Variant) IsLegalMove(b Board, row int, col int, val int) bool {
+	_, broken := v.ApplyMove(b, row, col, val)
+	return len(broken) == 0
+}
+
+// Places val like Board.ApplyMove, and returns the rules of the variant the
+// value then breaks. Returns no board and the error if the rules are not
+// valid for the board.
+func (v Variant) ApplyMove(b Board, row int, col int, val int) (Board, []error) {
+	s, err := b.shape()
+	if err != nil {
+		return nil, []error{err}
+	}
+	s, err = v.shape(s)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return b.applyMove(s, row, col, val)
+}
+
+// Places val at column col of row row of a copy of the board, which has the
+// shape s, and returns the rules it breaks.
+func (b Board) applyMove(s *shape, row int, col int, val int) (Board, []error) {
+	if err := b.checkRange(s); err != nil {
+		return nil, []error{err}
+	}
+	if row < 0 || row >= s.size || col < 0 || col >= s.size {
+		return nil, []error{fmt.Errorf("Move at row %d and column %d is not on the board", row+1, col+1)}
+	}
+	if val < 0 || val > s.size {
+		return nil, []error{fmt.Errorf("Move value %d is not a value of the board", val)}
+	}
+
+	board := make(Board, len(b))
+	copy(board, b)
+	pos := s.cell(row, col)
+	board[pos] = val
+	return board, board.broken(s, pos)
+}
+
+// Returns the rules broken by the value of the cell at pos, with the
+// other values of the board.
+func (b Board) broken(s *shape, pos int) []error {
+	var broken []error
+	val := b[pos]
+	twice := func(unit int) {
+		for _, peer := range s.unitCells[unit] {
+			if peer != pos && b[peer] == val {
+				error := fmt.Sprintf("Number %d appears twice in %s", val, s.unitName(unit))
+				broken = append(broken, errors.New(error))
+				return
+			}
+		}
+	}
+	if val != 0 {
+		for kind := 0; kind < 3; kind++ {
+			twice(s.unit(pos, kind))
+		}
+		for _, extra := range s.extraUnits[pos] {
+			twice(3*s.size + extra)
+		}
+	}
+	for _, c := range s.cellConstraints[pos] {
+		if err := s.constraints[c].check(b); err != nil {
+			broken = append(broken, err)
+		}
+	}
+	return broken
+}
@@ -0,0 +1,58 @@
+package sudoku
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The notes of a player on a board being solved, kept along with the board
+// by game clients but not part of the puzzle.
+type Notes struct {
+	// The candidates the player has pencilled into each cell, null for none.
+	PencilMarks [][]int `json:"pencilmarks,omitempty"`
+	// The marks the player has put on each cell, ie. a colour, empty for
+	// none.
+	Marks []string `json:"marks,omitempty"`
+}
+
+// Reads the notes from the json object of a puzzle, see Variant.ParsePuzzle,
+// which has no notes in the other formats. Returns an error if they are not
+// valid for the board the puzzle was parsed to.
+func ParseNotes(data []byte, b Board) (Notes, error) {
+	notes := Notes{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		return notes, nil
+	}
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return Notes{}, err
+	}
+	if err := notes.check(b); err != nil {
+		return Notes{}, err
+	}
+	return notes, nil
+}
+
+// Returns an error if the notes do not have a cell for each cell of the
+// board, or pencil marks that are not values.
+func (n Notes) check(b Board) error {
+	size := boardSize(len(b))
+	if n.PencilMarks != nil && len(n.PencilMarks) != len(b) {
+		error := fmt.Sprintf("Pencil marks are given for %d cells, expected %d", len(n.PencilMarks), len(b))
+		return errors.New(error)
+	}
+	for pos, marks := range n.PencilMarks {
+		for _, val := range marks {
+			if val < 1 || val > size {
+				error := fmt.Sprintf("Pencil mark %d of cell %d is not a value", val, pos)
+				return errors.New(error)
+			}
+		}
+	}
+	if n.Marks != nil && len(n.Marks) != len(b) {
+		error := fmt.Sprintf("Marks are given for %d cells, expected %d", len(n.Marks), len(b))
+		return errors.New(error)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// Solves the board with s.Workers goroutines. The search is expanded breadth
+// first until there is a branch per worker (or the branches run out), then
+// each worker solves branches sequentially, and the first solution found, or
+// error other than ErrUnsolvable, cancels the rest.
+func (s Solver) solveParallel(ctx context.Context, b Board) (Board, error) {
+	shape, err := s.Variant.validate(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+
+	// Count the work of every worker, each counting its own.
+	var stats Stats
+	var statsLock sync.Mutex
+	if s.Stats != nil {
+		defer func(start time.Time) {
+			stats.Elapsed = time.Since(start)
+			*s.Stats = stats
+		}(time.Now())
+	}
+
+	if s.Logger != nil {
+		s.Logger.Debug("Selected solver engine", "engine", Backtracking.String(), "reason", "parallel search", "cells", len(b), "workers", s.Workers)
+	}
+
+	// Expand the search, a branch solved by propagation alone is a solution.
+	branches := []Board{b}
+	for len(branches) > 0 && len(branches) < s.Workers {
+		search := newBacktracker(shape, branches[0])
+		search.stats = &stats
+		stats.Nodes++
+		branches = branches[1:]
+		best, candidates, ok := search.propagate()
+		if !ok {
+			continue
+		}
+		if best < 0 {
+			return search.grid.board(), nil
+		}
+		for ; candidates != 0; candidates &= candidates - 1 {
+			search.set(best, bits.TrailingZeros32(candidates))
+			branches = append(branches, search.grid.board())
+			search.unset(best)
+		}
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan Board, len(branches))
+	for _, branch := range branches {
+		jobs <- branch
+	}
+	close(jobs)
+
+	// The first solution, or the first error other than the branch having
+	// no solution, ends the search.
+	var once sync.Once
+	var result Board
+	var failure error
+	var wg sync.WaitGroup
+	for i := 0; i < s.Workers && i < len(branches); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sequential := Solver{Engine: s.Engine, Variant: s.Variant, Observe: s.Observe}
+			if s.Stats != nil {
+				sequential.Stats = &Stats{}
+			}
+			for branch := range jobs {
+				solution, err := sequential.Solve(ctx, branch)
+				if s.Stats != nil {
+					statsLock.Lock()
+					stats.add(*sequential.Stats)
+					statsLock.Unlock()
+				}
+				if err == nil {
+					once.Do(func() {
+						result = solution
+						cancel()
+					})
+					return
+				}
+				if errors.Is(err, ErrUnsolvable) {
+					continue
+				}
+				// Cancelled by a solution found by another worker, or by
+				// the caller.
+				if ctx.Err() != nil {
+					return
+				}
+				once.Do(func() {
+					failure = err
+					cancel()
+				})
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	if result != nil {
+		return result, nil
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrUnsolvable
+}
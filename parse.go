@@ -0,0 +1,221 @@
+package sudoku
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Parses a board, returns the board or an error if the input is empty, not
+// in a known format or not a valid board (wrapping ErrInvalidBoard).
+//
+// The input is either a json array of 81 numbers, a json array of 9 rows of 9
+// numbers, the 81 character line format where the digits 1-9 are givens
+// and '.' or '0' are blanks (or a number or character per cell, with A-Z for
+// 10 and up, for the other sizes), a .sdk file (see SDK) of which the givens are
+// returned, a Simple Sudoku .ss grid with '|' separators (see ParseSS), or 9
+// rows of comma separated cells (see ParseCSV), or an f-puzzles or SudokuPad
+// link (see ParseURL).
+func Parse(data []byte) (Board, error) {
+	return Variant{}.Parse(data)
+}
+
+// Parses a board like Parse, but validates it under the variant's rules
+// instead of the classic ones.
+func (v Variant) Parse(data []byte) (Board, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("No input")
+	}
+
+	var board Board
+	var err error
+	switch DetectFormat(data) {
+	case "url":
+		board, err = parseURL(string(data))
+	case "sdk":
+		board, err = parseSDK(data)
+	case "json":
+		board, err = parseJSON(data)
+	case "ss":
+		board, err = parseSS(data)
+	case "csv":
+		board, err = parseCSV(data)
+	default:
+		board, err = parseLine(string(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate that board is valid.
+	_, err = v.IsValid(board)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+
+	return board, nil
+}
+
+// Returns the format Parse reads the input as: "url", "sdk", "json", "ss",
+// "csv" or "line".
+func DetectFormat(data []byte) string {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("http://")) || bytes.HasPrefix(data, []byte("https://")):
+		return "url"
+	case len(data) > 0 && data[0] == '#' || len(data) > 1 && data[0] == '[' && data[1] >= 'A' && data[1] <= 'Z':
+		// .sdk metadata or a section header.
+		return "sdk"
+	case len(data) > 0 && data[0] == '[':
+		return "json"
+	case bytes.IndexByte(data, '|') >= 0:
+		return "ss"
+	case bytes.IndexByte(data, ',') >= 0:
+		return "csv"
+	case bytes.IndexByte(data, '\n') >= 0:
+		// .sdk rows.
+		return "sdk"
+	}
+	return "line"
+}
+
+// A board with the rules of its variant, and the notes of the player solving
+// it if any, written as a json object with the board as an array, ie.
+// {"board":[...],"white":[[0,1],...],"alldots":true}.
+type Puzzle struct {
+	Board Board `json:"board"`
+	Variant
+	Notes
+}
+
+// A board with the rules of its variant, ie. {"board":"...","cages":[...]}.
+type puzzle struct {
+	Board json.RawMessage `json:"board"`
+	Variant
+}
+
+// Parses a board like Parse, or a json object with the board, in any of the
+// formats (the text ones as a json string), and the rules of its variant
+// added to v, ie. {"board":"...","cages":[{"cells":[0,1],"sum":3},...]}.
+// Without a board, the puzzle has no givens. Returns the board and the
+// variant it is valid under.
+func (v Variant) ParsePuzzle(data []byte) (Board, Variant, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		board, err := v.Parse(data)
+		return board, v, err
+	}
+
+	p := puzzle{Variant: v}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, v, err
+	}
+	if len(p.Board) == 0 {
+		cells := 81
+		if p.Regions != nil {
+			cells = len(p.Regions)
+		}
+		board := make(Board, cells)
+		if _, err := p.Variant.IsValid(board); err != nil {
+			return nil, p.Variant, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+		}
+		return board, p.Variant, nil
+	}
+	board := []byte(p.Board)
+	var text string
+	if json.Unmarshal(p.Board, &text) == nil {
+		board = []byte(text)
+	}
+	result, err := p.Variant.Parse(board)
+	return result, p.Variant, err
+}
+
+// Parses a board from the 81 character line format, ie:
+// "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..",
+// or the other sizes from a character per cell, with A-Z for 10 and up.
+func ParseLine(line string) (Board, error) {
+	board, err := parseLine(line)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = board.IsValid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+
+	return board, nil
+}
+
+func parseJSON(data []byte) (Board, error) {
+	// Nested rows, ie. [[row],[row],...].
+	if inner := bytes.TrimSpace(data[1:]); len(inner) > 0 && inner[0] == '[' {
+		rows := [][]int{}
+		err := json.Unmarshal(data, &rows)
+		if err != nil {
+			return nil, err
+		}
+		return FromRows(rows)
+	}
+
+	board := Board{}
+	err := json.Unmarshal(data, &board)
+	if err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// Builds a board from 9 rows of 9 numbers (or as many rows as the other
+// sizes have), the board is not validated.
+func FromRows(rows [][]int) (Board, error) {
+	if _, ok := shapeOf(len(rows) * len(rows)); !ok {
+		return nil, ErrBadSize
+	}
+
+	board := make(Board, 0, len(rows)*len(rows))
+	for y, row := range rows {
+		if len(row) != len(rows) {
+			error := fmt.Sprintf("Row %d does not have %d numbers", y+1, len(rows))
+			return nil, errors.New(error)
+		}
+		board = append(board, row...)
+	}
+	return board, nil
+}
+
+func parseLine(line string) (Board, error) {
+	s, ok := shapeOf(len(line))
+	if !ok {
+		return nil, fmt.Errorf("%w: the line is %d characters long", ErrBadSize, len(line))
+	}
+
+	board := make(Board, len(line))
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '.' || c == '0':
+			board[i] = 0
+		case c >= '1' && c <= '9':
+			board[i] = int(c - '0')
+		case s.size > 9 && c >= 'A' && c < byte('A'+s.size-9):
+			board[i] = int(c-'A') + 10
+		case s.size > 9 && c >= 'a' && c < byte('a'+s.size-9):
+			board[i] = int(c-'a') + 10
+		default:
+			error := fmt.Sprintf("Unexpected character %q at position: %d", c, i)
+			return nil, errors.New(error)
+		}
+	}
+	return board, nil
+}
+
+// Returns the givens of a .sdk file, which are not validated.
+func parseSDK(data []byte) (Board, error) {
+	sdk, err := readSDK(data)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.Puzzle, nil
+}
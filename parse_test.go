@@ -0,0 +1,334 @@
+package sudoku
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// The solution of easyLine.
+const easySolution = "483921657967345821251876493548132976729564138136798245372689514814253769695417382"
+
+func TestParse(t *testing.T) {
+	board, err := ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		input  string
+		format string
+	}{
+		{"line", easyLine, "line"},
+		{"line with zeros", strings.Replace(easyLine, ".", "0", -1), "line"},
+		{"line with spaces", "  " + easyLine + "\n", "line"},
+		{"json", "[0,0,3,0,2,0,6,0,0,9,0,0,3,0,5,0,0,1,0,0,1,8,0,6,4,0,0,0,0,8,1,0,2,9,0,0,7,0,0,0,0,0,0,0,8,0,0,6,7,0,8,2,0,0,0,0,2,6,0,9,5,0,0,8,0,0,2,0,3,0,0,9,0,0,5,0,1,0,3,0,0]", "json"},
+		{"json rows", toJSONRows(board), "json"},
+		{"sdk", (&SDK{Puzzle: board}).String(), "sdk"},
+		{"sdk with metadata", (&SDK{Puzzle: board, Metadata: map[byte]string{'A': "John Doe"}}).String(), "sdk"},
+		{"ss", board.SS(), "ss"},
+		{"pretty", board.String(), "ss"},
+		{"csv", board.CSV(), "csv"},
+		{"url", board.FPuzzlesURL(nil), "url"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if format := DetectFormat([]byte(test.input)); format != test.format {
+				t.Errorf("got format %q, want %q", format, test.format)
+			}
+			parsed, err := Parse([]byte(test.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Line() != easyLine {
+				t.Errorf("got %s, want %s", parsed.Line(), easyLine)
+			}
+		})
+	}
+}
+
+// Returns the board as a json array of rows.
+func toJSONRows(b Board) string {
+	rows := []string{}
+	for _, row := range b.Rows() {
+		cells := []string{}
+		for _, val := range row {
+			cells = append(cells, string(rune('0'+val)))
+		}
+		rows = append(rows, "["+strings.Join(cells, ",")+"]")
+	}
+	return "[" + strings.Join(rows, ",") + "]"
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{"empty", " \n", nil},
+		{"short line", easyLine[1:], ErrBadSize},
+		{"unexpected character", "x" + easyLine[1:], nil},
+		{"letter on 9x9", "A" + easyLine[1:], nil},
+		{"duplicate in row", "11" + easyLine[2:], ErrInvalidBoard},
+		{"duplicate in column", "9" + easyLine[1:], ErrInvalidBoard},
+		{"out of range", "[10" + strings.Repeat(",0", 80) + "]", ErrInvalidBoard},
+		{"json rows of 8", "[[1,2,3,4,5,6,7,8]]", ErrBadSize},
+		{"json short row", "[[1,2,3,4],[1,2,3,4],[1,2,3],[1,2,3,4]]", nil},
+		{"sdk unknown section", "[Notes]\n" + strings.Repeat(easyLine[:9]+"\n", 9), nil},
+		{"sdk 8 rows", "#AJohn Doe\n" + strings.Repeat(".........\n", 8), nil},
+		{"ss short", "|...|...|...|", nil},
+		{"csv 8 rows", strings.Repeat(",,,,,,,,\n", 8), nil},
+		{"csv number", strings.Repeat("x,,,,,,,,\n", 9), nil},
+		{"sudokupad", "https://sudokupad.app/scl1234", nil},
+		{"url without puzzle", "https://www.f-puzzles.com/", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.input))
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			if test.err != nil && !errors.Is(err, test.err) {
+				t.Errorf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestParseSizes(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"4x4", "1..4..1..4..3..2"},
+		{"6x6", "1.....4.....2.....5.....3.....6....."},
+		{"16x16", "1.......G" + strings.Repeat(".", 247)},
+		{"16x16 lowercase", "1.......g" + strings.Repeat(".", 247)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			board, err := ParseLine(test.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if line := board.Line(); line != strings.ToUpper(test.line) {
+				t.Errorf("got %s, want %s", line, strings.ToUpper(test.line))
+			}
+		})
+	}
+}
+
+func TestSDK(t *testing.T) {
+	puzzle, err := ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := ParseLine("48392.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		sdk  SDK
+	}{
+		{"puzzle", SDK{Puzzle: puzzle, Metadata: map[byte]string{}}},
+		{"metadata", SDK{Puzzle: puzzle, Metadata: map[byte]string{'A': "John Doe", 'D': "An easy one", 'L': "Easy"}}},
+		{"state", SDK{Puzzle: puzzle, State: state, Metadata: map[byte]string{'C': "Half way"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			text := test.sdk.String()
+			sdk, err := ParseSDK([]byte(text))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sdk.String() != text {
+				t.Errorf("got\n%s\nwant\n%s", sdk.String(), text)
+			}
+			if sdk.Puzzle.Line() != easyLine {
+				t.Errorf("got puzzle %s, want %s", sdk.Puzzle.Line(), easyLine)
+			}
+			if (sdk.State == nil) != (test.sdk.State == nil) {
+				t.Errorf("got state %v, want %v", sdk.State, test.sdk.State)
+			}
+			for letter, value := range test.sdk.Metadata {
+				if sdk.Metadata[letter] != value {
+					t.Errorf("got %q for #%c, want %q", sdk.Metadata[letter], letter, value)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSDKErrors(t *testing.T) {
+	rows := strings.Repeat(".........\n", 9)
+	tests := []struct {
+		name  string
+		input string
+		err   error
+	}{
+		{"no letter", "#\n" + rows, nil},
+		{"long row", strings.Repeat("..........\n", 9), nil},
+		{"invalid puzzle", "11.......\n" + strings.Repeat(".........\n", 8), ErrInvalidBoard},
+		{"invalid state", "[Puzzle]\n" + rows + "[State]\n11.......\n" + strings.Repeat(".........\n", 8), ErrInvalidBoard},
+		{"short state", "[Puzzle]\n" + rows + "[State]\n.........\n", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseSDK([]byte(test.input))
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			if test.err != nil && !errors.Is(err, test.err) {
+				t.Errorf("got %v, want %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestSDM(t *testing.T) {
+	text := "#AJohn Doe\n#DDaily puzzles\n" + easyLine + "\n" + strings.Repeat(".", 81)
+	sdm, err := ParseSDM([]byte(text + "\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sdm.Puzzles) != 2 || sdm.Puzzles[0].Line() != easyLine {
+		t.Errorf("got puzzles %v, want %s and an empty board", sdm.Puzzles, easyLine)
+	}
+	if sdm.Metadata['D'] != "Daily puzzles" {
+		t.Errorf("got description %q, want %q", sdm.Metadata['D'], "Daily puzzles")
+	}
+	if sdm.String() != text {
+		t.Errorf("got\n%s\nwant\n%s", sdm.String(), text)
+	}
+
+	_, err = ParseSDM([]byte(easyLine + "\n11" + easyLine[2:]))
+	if !errors.Is(err, ErrInvalidBoard) || !strings.HasPrefix(err.Error(), "Line 2: ") {
+		t.Errorf("got %v, want an invalid board on line 2", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	board, err := ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := ParseLine(easySolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	formats := []struct {
+		name  string
+		write func(Board) string
+		read  func(string) (Board, error)
+	}{
+		{"line", Board.Line, ParseLine},
+		{"ss", Board.SS, func(s string) (Board, error) { return ParseSS([]byte(s)) }},
+		{"csv", Board.CSV, func(s string) (Board, error) { return ParseCSV([]byte(s)) }},
+		{"url", func(b Board) string { return b.FPuzzlesURL(solution) }, ParseURL},
+		{"sdk", func(b Board) string { return (&SDK{Puzzle: b}).String() }, func(s string) (Board, error) {
+			sdk, err := ParseSDK([]byte(s))
+			if err != nil {
+				return nil, err
+			}
+			return sdk.Puzzle, nil
+		}},
+	}
+	for _, format := range formats {
+		for name, b := range map[string]Board{"puzzle": board, "solution": solution, "empty": make(Board, 81)} {
+			t.Run(format.name+"/"+name, func(t *testing.T) {
+				parsed, err := format.read(format.write(b))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if parsed.Line() != b.Line() {
+					t.Errorf("got %s, want %s", parsed.Line(), b.Line())
+				}
+			})
+		}
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	board, err := ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := strings.TrimPrefix(board.FPuzzlesURL(nil), "https://www.f-puzzles.com/?load=")
+	links := []string{
+		"https://www.f-puzzles.com/?load=" + data,
+		"https://www.f-puzzles.com/?other=1&load=" + data,
+		"https://sudokupad.app/fpuzzles" + data,
+		"https://sudokupad.app/?puzzle=fpuzzles" + data,
+	}
+	for _, link := range links {
+		parsed, err := ParseURL(link)
+		if err != nil {
+			t.Errorf("got %v for %s", err, link)
+			continue
+		}
+		if parsed.Line() != easyLine {
+			t.Errorf("got %s for %s, want %s", parsed.Line(), link, easyLine)
+		}
+	}
+}
+
+func TestLZString(t *testing.T) {
+	inputs := []string{
+		"",
+		"a",
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		"hello, world",
+		`{"size":9,"grid":[[{"value":1,"given":true},{}]]}`,
+		"æøå ☃ 数独",
+		strings.Repeat("abcabd", 100),
+	}
+	for _, input := range inputs {
+		compressed := lzCompressBase64(input)
+		decompressed, err := lzDecompressBase64(compressed)
+		if err != nil {
+			t.Errorf("got %v decompressing %q", err, input)
+			continue
+		}
+		if decompressed != input {
+			t.Errorf("got %q, want %q", decompressed, input)
+		}
+	}
+}
+
+func TestParsePuzzle(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  string
+		cages int
+		err   error
+	}{
+		{"line", easyLine, easyLine, 0, nil},
+		{"board as string", `{"board":"` + easyLine + `"}`, easyLine, 0, nil},
+		{"no board", `{"cages":[{"cells":[0,1],"sum":3}]}`, strings.Repeat(".", 81), 1, nil},
+		{"board and cages", `{"board":"12` + strings.Repeat(".", 79) + `","cages":[{"cells":[0,1],"sum":3}]}`, "12" + strings.Repeat(".", 79), 1, nil},
+		{"breaks a cage", `{"board":"13` + strings.Repeat(".", 79) + `","cages":[{"cells":[0,1],"sum":3}]}`, "", 0, ErrInvalidBoard},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			board, variant, err := Variant{}.ParsePuzzle([]byte(test.input))
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("got %v, want %v", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if board.Line() != test.line {
+				t.Errorf("got %s, want %s", board.Line(), test.line)
+			}
+			if len(variant.Cages) != test.cages {
+				t.Errorf("got %d cages, want %d", len(variant.Cages), test.cages)
+			}
+		})
+	}
+}
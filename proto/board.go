@@ -0,0 +1,22 @@
+package sudokupb
+
+import "github.com/dhedegaard/sudoku.go"
+
+// Converts a board to a message.
+func NewBoard(board sudoku.Board) *Board {
+	cells := make([]int32, len(board))
+	for i, val := range board {
+		cells[i] = int32(val)
+	}
+	return &Board{Cells: cells}
+}
+
+// Converts the message to a board, nil is treated as an empty (invalid)
+// board.
+func (x *Board) Sudoku() sudoku.Board {
+	result := make(sudoku.Board, len(x.GetCells()))
+	for i, val := range x.GetCells() {
+		result[i] = int(val)
+	}
+	return result
+}
@@ -0,0 +1,5 @@
+package sudokupb
+
+// Regenerate the gRPC code after changing sudoku.proto, needs protoc with
+// the protoc-gen-go and protoc-gen-go-grpc plugins.
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative proto/sudoku.proto
@@ -0,0 +1,168 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// How hard a puzzle is for a human, based on the hardest technique needed.
+type Difficulty int
+
+const (
+	// Solvable with singles only.
+	Easy Difficulty = iota + 1
+	// Needs intersections, pairs or triples.
+	Medium
+	// Needs fish, ie. x-wings or swordfish.
+	Hard
+	// Needs chains, ie. xy-wings.
+	Expert
+	// Cannot be solved without guessing.
+	Extreme
+)
+
+var difficultyNames = map[Difficulty]string{
+	Easy:    "easy",
+	Medium:  "medium",
+	Hard:    "hard",
+	Expert:  "expert",
+	Extreme: "extreme",
+}
+
+func (d Difficulty) String() string {
+	if name, ok := difficultyNames[d]; ok {
+		return name
+	}
+	return fmt.Sprintf("Difficulty(%d)", int(d))
+}
+
+// Encodes the difficulty as its name, ie. in json.
+func (d Difficulty) MarshalText() ([]byte, error) {
+	if _, ok := difficultyNames[d]; !ok {
+		return nil, fmt.Errorf("Unknown difficulty: %d", int(d))
+	}
+	return []byte(d.String()), nil
+}
+
+// Decodes the difficulty from its name.
+func (d *Difficulty) UnmarshalText(text []byte) error {
+	parsed, err := ParseDifficulty(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Returns the difficulty with the given name, ie. "hard".
+func ParseDifficulty(name string) (Difficulty, error) {
+	for d, n := range difficultyNames {
+		if n == name {
+			return d, nil
+		}
+	}
+	return 0, errors.New("Unknown difficulty: " + name)
+}
+
+// The rating of a puzzle.
+type Rating struct {
+	// The sum of the weights of every technique application needed to solve
+	// the puzzle, harder techniques weigh more.
+	Score int `json:"score"`
+	// The difficulty of the hardest technique needed.
+	Difficulty Difficulty `json:"difficulty"`
+	// The name of the hardest technique needed, "guess" if the puzzle cannot
+	// be solved logically.
+	Technique string `json:"technique"`
+}
+
+// The weight of guessing a cell, when no technique applies.
+const guessWeight = 100
+
+// Rates how hard the puzzle is for a human, by solving it with human
+// techniques, easiest first. Returns an error wrapping ErrInvalidBoard,
+// ErrUnsolvable or ErrNotUnique if the board does not have exactly one
+// solution.
+func (b Board) Rate() (Rating, error) {
+	return b.rate(context.Background(), Variant{})
+}
+
+// Like Rate, but gives up and returns the context's error as soon as ctx is
+// cancelled or its deadline is exceeded.
+func (b Board) RateContext(ctx context.Context) (Rating, error) {
+	return b.rate(ctx, Variant{})
+}
+
+// Rates how hard the puzzle is for a human like Board.Rate, under the
+// variant's rules.
+func (v Variant) Rate(b Board) (Rating, error) {
+	return b.rate(context.Background(), v)
+}
+
+// Rates the puzzle like RateContext, under the variant's rules.
+func (b Board) rate(ctx context.Context, variant Variant) (Rating, error) {
+	solver := Solver{Engine: DancingLinks, Variant: variant}
+	count, err := solver.CountSolutions(ctx, b, 2)
+	if err != nil {
+		return Rating{}, err
+	}
+	if count == 0 {
+		return Rating{}, ErrUnsolvable
+	}
+	if count > 1 {
+		return Rating{}, ErrNotUnique
+	}
+	solution, err := solver.Solve(ctx, b)
+	if err != nil {
+		return Rating{}, err
+	}
+
+	rating := Rating{Difficulty: Easy}
+	s, _ := variant.validate(b)
+	g := newLogicGrid(s, b)
+	for !g.solved() {
+		if err := ctx.Err(); err != nil {
+			return Rating{}, err
+		}
+		s, t := g.next()
+		if s == nil {
+			// Stuck, guess the cell with the fewest candidates using the
+			// known solution.
+			guess := -1
+			for cell, mask := range g.candidates {
+				if g.board[cell] == 0 && (guess < 0 || bits.OnesCount32(mask) < bits.OnesCount32(g.candidates[guess])) {
+					guess = cell
+				}
+			}
+			g.place(guess, solution[guess])
+			rating.Score += guessWeight
+			if rating.Difficulty < Extreme {
+				rating.Difficulty = Extreme
+				rating.Technique = "guess"
+			}
+			continue
+		}
+
+		g.apply(s)
+		rating.Score += t.weight
+		if t.level > rating.Difficulty || rating.Technique == "" {
+			rating.Difficulty = t.level
+			rating.Technique = t.name
+		} else if t.level == rating.Difficulty && t.weight > techniqueWeight(rating.Technique) {
+			rating.Technique = t.name
+		}
+	}
+	return rating, nil
+}
+
+// Returns the weight of the named technique.
+func techniqueWeight(name string) int {
+	for _, t := range techniques {
+		if t.name == name {
+			return t.weight
+		}
+	}
+	return guessWeight
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The stylesheet of the html documents, inlined so the file is self
+// contained.
+const htmlStyle = `body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; border: 3px solid #222; margin: 1em 0; }
+td { width: 2em; height: 2em; border: 1px solid #999; text-align: center; font-size: 1.5em; }
+td.given { font-weight: bold; }
+td.solved { color: #1565c0; }
+tr:nth-child(3n) td { border-bottom: 3px solid #222; }
+td:nth-child(3n) { border-right: 3px solid #222; }
+summary { cursor: pointer; }
+`
+
+// Writes the board as a html table.
+func htmlTable(buffer *bytes.Buffer, board, givens sudoku.Board) {
+	buffer.WriteString("<table>\n")
+	for y := 0; y < 9; y++ {
+		buffer.WriteString("<tr>")
+		for x := 0; x < 9; x++ {
+			i := y*9 + x
+			switch {
+			case board[i] == 0:
+				buffer.WriteString("<td></td>")
+			case isGiven(givens, board, i):
+				fmt.Fprintf(buffer, `<td class="given">%d</td>`, board[i])
+			default:
+				fmt.Fprintf(buffer, `<td class="solved">%d</td>`, board[i])
+			}
+		}
+		buffer.WriteString("</tr>\n")
+	}
+	buffer.WriteString("</table>\n")
+}
+
+// Draws the board as a standalone html document, with the styles inlined.
+// If solution is not nil, it is included below the board behind a "Show
+// solution" toggle, with the filled cells of the board as its givens.
+func HTML(board, givens, solution sudoku.Board) []byte {
+	buffer := bytes.NewBufferString("")
+	buffer.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Sudoku</title>\n")
+	buffer.WriteString("<style>\n" + htmlStyle + "</style>\n</head>\n<body>\n")
+	htmlTable(buffer, board, givens)
+	if solution != nil {
+		buffer.WriteString("<details>\n<summary>Show solution</summary>\n")
+		htmlTable(buffer, solution, board)
+		buffer.WriteString("</details>\n")
+	}
+	buffer.WriteString("</body>\n</html>")
+	return buffer.Bytes()
+}
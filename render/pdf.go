@@ -0,0 +1,210 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// A board on a pdf sheet, with its title and label (ie. the difficulty)
+// printed above it.
+type PDFBoard struct {
+	Board  sudoku.Board
+	Givens sudoku.Board
+	Title  string
+	Label  string
+}
+
+// The layout of an A4 page, in points.
+const (
+	pdfWidth  = 595
+	pdfHeight = 842
+	pdfMargin = 50
+	pdfGap    = 30
+	// The height of the title above each board.
+	pdfHeader = 24
+)
+
+// The widths of the printable ascii characters in Helvetica, in thousandths
+// of the font size, from ' ' to '~'.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 222, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	222, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// Returns the width of text in Helvetica at the given font size.
+func textWidth(text string, size float64) float64 {
+	width := 0
+	for _, c := range text {
+		if c >= ' ' && c <= '~' {
+			width += helveticaWidths[c-' ']
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// Escapes text as a pdf string, replacing characters outside of ascii.
+func pdfString(text string) string {
+	result := strings.Builder{}
+	result.WriteByte('(')
+	for _, c := range text {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			result.WriteByte('\\')
+			result.WriteRune(c)
+		case c >= ' ' && c <= '~':
+			result.WriteRune(c)
+		default:
+			result.WriteByte('?')
+		}
+	}
+	result.WriteByte(')')
+	return result.String()
+}
+
+// Draws a board with its top left corner at (x, y), in points from the
+// bottom left of the page.
+func pdfBoard(content *bytes.Buffer, board, givens sudoku.Board, x, y, size float64) {
+	cell := size / 9
+
+	// The thin cell lines first, then the box lines on top.
+	content.WriteString("0.6 G 0.5 w\n")
+	for i := 1; i < 9; i++ {
+		if i%3 != 0 {
+			at := float64(i) * cell
+			fmt.Fprintf(content, "%.2f %.2f m %.2f %.2f l S\n", x+at, y, x+at, y-size)
+			fmt.Fprintf(content, "%.2f %.2f m %.2f %.2f l S\n", x, y-at, x+size, y-at)
+		}
+	}
+	content.WriteString("0.133 G 2 w\n")
+	for i := 3; i < 9; i += 3 {
+		at := float64(i) * cell
+		fmt.Fprintf(content, "%.2f %.2f m %.2f %.2f l S\n", x+at, y, x+at, y-size)
+		fmt.Fprintf(content, "%.2f %.2f m %.2f %.2f l S\n", x, y-at, x+size, y-at)
+	}
+	fmt.Fprintf(content, "%.2f %.2f %.2f %.2f re S\n", x, y-size, size, size)
+
+	fontSize := cell * 0.6
+	for i, val := range board {
+		if val == 0 {
+			continue
+		}
+		font, color := "F1", "0.082 0.396 0.753 rg"
+		if isGiven(givens, board, i) {
+			font, color = "F2", "0.133 g"
+		}
+		// Center the digit, its height is about 0.7 of the font size.
+		digitX := x + (float64(i%9)+0.5)*cell - textWidth("0", fontSize)/2
+		digitY := y - (float64(i/9)+0.5)*cell - fontSize*0.35
+		fmt.Fprintf(content, "BT %s /%s %.2f Tf %.2f %.2f Td (%d) Tj ET\n", color, font, fontSize, digitX, digitY, val)
+	}
+}
+
+// Lays out the boards as a printable A4 pdf document, perPage boards per
+// page, each with its title on the left and label on the right above it.
+func PDF(boards []PDFBoard, perPage int) []byte {
+	return pdfDocument(pdfPages(boards, perPage))
+}
+
+// Boards laid out on pages of their own in a PDFBook, ie. the solutions.
+type PDFSection struct {
+	Boards  []PDFBoard
+	PerPage int
+}
+
+// Lays out the sections as a printable A4 pdf document like PDF, each
+// starting on a new page with its own number of boards per page.
+func PDFBook(sections []PDFSection) []byte {
+	pages := []*bytes.Buffer{}
+	for _, section := range sections {
+		if len(section.Boards) > 0 {
+			pages = append(pages, pdfPages(section.Boards, section.PerPage)...)
+		}
+	}
+	return pdfDocument(pages)
+}
+
+// Returns the content streams of the pages laying out the boards, perPage
+// boards per page.
+func pdfPages(boards []PDFBoard, perPage int) []*bytes.Buffer {
+	if perPage < 1 {
+		perPage = 1
+	}
+	columns := 1
+	if perPage > 2 {
+		columns = 2
+	}
+	rows := (perPage + columns - 1) / columns
+	slotWidth := float64(pdfWidth-2*pdfMargin-(columns-1)*pdfGap) / float64(columns)
+	slotHeight := float64(pdfHeight-2*pdfMargin-(rows-1)*pdfGap) / float64(rows)
+	size := min(slotWidth, slotHeight-pdfHeader)
+
+	// The content stream of each page.
+	pages := []*bytes.Buffer{}
+	for i, board := range boards {
+		if i%perPage == 0 {
+			pages = append(pages, bytes.NewBuffer(nil))
+		}
+		content := pages[len(pages)-1]
+		slot := i % perPage
+		x := pdfMargin + float64(slot%columns)*(slotWidth+pdfGap) + (slotWidth-size)/2
+		y := pdfHeight - pdfMargin - float64(slot/columns)*(slotHeight+pdfGap)
+		if board.Title != "" {
+			fmt.Fprintf(content, "BT 0.133 g /F2 14 Tf %.2f %.2f Td %s Tj ET\n", x, y-14, pdfString(board.Title))
+		}
+		if board.Label != "" {
+			labelX := x + size - textWidth(board.Label, 12)
+			fmt.Fprintf(content, "BT 0.4 g /F1 12 Tf %.2f %.2f Td %s Tj ET\n", labelX, y-14, pdfString(board.Label))
+		}
+		pdfBoard(content, board.Board, board.Givens, x, y-pdfHeader, size)
+	}
+	return pages
+}
+
+// Returns the pdf document with the pages, an empty page if there are none.
+func pdfDocument(pages []*bytes.Buffer) []byte {
+	if len(pages) == 0 {
+		pages = append(pages, bytes.NewBuffer(nil))
+	}
+
+	// Objects 1-4 are the catalog, the page tree and the fonts, followed by
+	// each page and its content.
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold >>",
+	}
+	kids := []string{}
+	for _, content := range pages {
+		page := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfWidth, pdfHeight, page+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	buffer := bytes.NewBufferString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, buffer.Len())
+		fmt.Fprintf(buffer, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buffer.Len()
+	fmt.Fprintf(buffer, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buffer.Bytes()
+}
@@ -0,0 +1,159 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+var (
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	grey  = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	black = color.RGBA{0x22, 0x22, 0x22, 0xff}
+	blue  = color.RGBA{0x15, 0x65, 0xc0, 0xff}
+)
+
+// A point in a cell, from (0,0) in the top left corner to (1,1) in the bottom
+// right.
+type point struct{ x, y float64 }
+
+// Returns the points of an arc around (cx,cy), from angle a to b in degrees,
+// clockwise from the right.
+func arc(cx, cy, r, a, b float64) []point {
+	points := []point{}
+	for i := 0; i <= 24; i++ {
+		angle := (a + (b-a)*float64(i)/24) * math.Pi / 180
+		points = append(points, point{cx + r*math.Cos(angle), cy + r*math.Sin(angle)})
+	}
+	return points
+}
+
+// Joins lines and arcs into one stroke.
+func stroke(parts ...[]point) []point {
+	result := []point{}
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
+
+// The strokes drawing each digit, as connected points.
+var digits = [10][][]point{
+	1: {{{0.38, 0.28}, {0.53, 0.15}, {0.53, 0.85}}, {{0.38, 0.85}, {0.68, 0.85}}},
+	2: {stroke(arc(0.5, 0.35, 0.2, 190, 390), []point{{0.3, 0.85}, {0.72, 0.85}})},
+	3: {arc(0.5, 0.33, 0.17, 200, 450), arc(0.5, 0.665, 0.185, 270, 520)},
+	4: {{{0.63, 0.85}, {0.63, 0.15}, {0.27, 0.65}, {0.76, 0.65}}},
+	5: {stroke([]point{{0.7, 0.15}, {0.36, 0.15}, {0.33, 0.48}}, arc(0.5, 0.63, 0.22, 222, 510))},
+	6: {arc(0.5, 0.65, 0.2, 0, 360), {{0.3, 0.62}, {0.62, 0.15}}},
+	7: {{{0.28, 0.15}, {0.72, 0.15}, {0.42, 0.85}}},
+	8: {arc(0.5, 0.32, 0.16, 0, 360), arc(0.5, 0.66, 0.2, 0, 360)},
+	9: {arc(0.5, 0.35, 0.2, 0, 360), {{0.7, 0.38}, {0.45, 0.85}}},
+}
+
+// Returns the distance from p to the segment from a to b.
+func distance(p, a, b point) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((p.x-a.x)*dx+(p.y-a.y)*dy)/length))
+	}
+	x, y := a.x+t*dx-p.x, a.y+t*dy-p.y
+	return math.Sqrt(x*x + y*y)
+}
+
+// Draws a digit into the square at rect, with lines width pixels wide,
+// blending its anti-aliased edges with the background.
+func drawDigit(img *image.RGBA, rect image.Rectangle, digit int, width float64, c color.RGBA) {
+	scale := float64(rect.Dx())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			p := point{(float64(x-rect.Min.X) + 0.5) / scale, (float64(y-rect.Min.Y) + 0.5) / scale}
+			d := math.Inf(1)
+			for _, line := range digits[digit] {
+				for i := 1; i < len(line); i++ {
+					d = math.Min(d, distance(p, line[i-1], line[i]))
+				}
+			}
+			coverage := math.Max(0, math.Min(1, width/2-d*scale+0.5))
+			if coverage > 0 {
+				background := img.RGBAAt(x, y)
+				img.SetRGBA(x, y, color.RGBA{
+					blend(background.R, c.R, coverage),
+					blend(background.G, c.G, coverage),
+					blend(background.B, c.B, coverage),
+					0xff,
+				})
+			}
+		}
+	}
+}
+
+func blend(from, to uint8, coverage float64) uint8 {
+	return uint8(float64(from) + (float64(to)-float64(from))*coverage + 0.5)
+}
+
+// Draws the board as a size x size image.
+func Image(board, givens sudoku.Board, size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{white}, image.Point{}, draw.Src)
+
+	// The grid is inset by the width of the outer lines.
+	thick := size/150 + 2
+	thin := size/450 + 1
+	cell := float64(size-2*thick) / 9
+	edge := func(i int) int {
+		return thick + int(float64(i)*cell+0.5)
+	}
+	fill := func(rect image.Rectangle, c color.RGBA) {
+		draw.Draw(img, rect, &image.Uniform{c}, image.Point{}, draw.Src)
+	}
+	line := func(i, width int, c color.RGBA) {
+		at := edge(i) - width/2
+		fill(image.Rect(at, thick, at+width, size-thick), c)
+		fill(image.Rect(thick, at, size-thick, at+width), c)
+	}
+	for i := 1; i < 9; i++ {
+		if i%3 != 0 {
+			line(i, thin, grey)
+		}
+	}
+	line(3, thick, black)
+	line(6, thick, black)
+	// The outer border.
+	fill(image.Rect(0, 0, size, thick), black)
+	fill(image.Rect(0, size-thick, size, size), black)
+	fill(image.Rect(0, 0, thick, size), black)
+	fill(image.Rect(size-thick, 0, size, size), black)
+
+	for i, val := range board {
+		if val == 0 {
+			continue
+		}
+		rect := image.Rect(edge(i%9), edge(i/9), edge(i%9+1), edge(i/9+1)).Inset(int(cell / 8))
+		rect.Max.Y = rect.Min.Y + rect.Dx()
+		if isGiven(givens, board, i) {
+			drawDigit(img, rect, val, cell/11, black)
+		} else {
+			drawDigit(img, rect, val, cell/16, blue)
+		}
+	}
+	return img
+}
+
+// Draws the board as a size x size png image.
+func PNG(board, givens sudoku.Board, size int) ([]byte, error) {
+	if size < 64 {
+		return nil, errors.New("Image size must be at least 64 pixels")
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := png.Encode(buffer, Image(board, givens, size)); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,34 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/dhedegaard/sudoku.go"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Returns the qr code of the board's 81 character line, which a phone can
+// scan to get the puzzle.
+func qr(board sudoku.Board) (*qrcode.QRCode, error) {
+	return qrcode.New(board.Line(), qrcode.Medium)
+}
+
+// Draws the qr code of the board's 81 character line as a size x size png
+// image.
+func QRPNG(board sudoku.Board, size int) ([]byte, error) {
+	code, err := qr(board)
+	if err != nil {
+		return nil, err
+	}
+	return code.PNG(size)
+}
+
+// Draws the qr code of the board's 81 character line with unicode blocks, two
+// modules per character, to scan off a terminal.
+func QRText(board sudoku.Board) (string, error) {
+	code, err := qr(board)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(code.ToSmallString(false), "\n"), nil
+}
@@ -0,0 +1,66 @@
+/* Package render draws sudoku boards as images and documents.
+ *
+ * Every renderer takes the board to draw and its givens, the cells of the
+ * original puzzle. Givens are drawn in bold, other filled cells (ie. those of
+ * a solution) in a lighter blue. If givens is nil, every filled cell is drawn
+ * as a given.
+ */
+package render
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The size of a cell in the svg, in user units.
+const svgCell = 50
+
+// Returns true if the cell at i is drawn as a given.
+func isGiven(givens sudoku.Board, board sudoku.Board, i int) bool {
+	if givens == nil {
+		return board[i] != 0
+	}
+	return givens[i] != 0
+}
+
+// Draws the board as a standalone svg document, which scales to the size it
+// is shown at.
+func SVG(board, givens sudoku.Board) []byte {
+	size := 9 * svgCell
+	buffer := bytes.NewBufferString("")
+	fmt.Fprintf(buffer, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="-2 -2 %d %d" width="%d" height="%d">`+"\n", size+4, size+4, size+4, size+4)
+	fmt.Fprintf(buffer, `<rect x="0" y="0" width="%d" height="%d" fill="#fff"/>`+"\n", size, size)
+
+	// The thin cell lines first, then the box lines on top.
+	buffer.WriteString(`<g stroke="#999" stroke-width="1">` + "\n")
+	for i := 1; i < 9; i++ {
+		if i%3 != 0 {
+			fmt.Fprintf(buffer, `<line x1="%d" y1="0" x2="%d" y2="%d"/>`+"\n", i*svgCell, i*svgCell, size)
+			fmt.Fprintf(buffer, `<line x1="0" y1="%d" x2="%d" y2="%d"/>`+"\n", i*svgCell, size, i*svgCell)
+		}
+	}
+	buffer.WriteString("</g>\n")
+	buffer.WriteString(`<g stroke="#222" stroke-width="3" fill="none">` + "\n")
+	for i := 3; i < 9; i += 3 {
+		fmt.Fprintf(buffer, `<line x1="%d" y1="0" x2="%d" y2="%d"/>`+"\n", i*svgCell, i*svgCell, size)
+		fmt.Fprintf(buffer, `<line x1="0" y1="%d" x2="%d" y2="%d"/>`+"\n", i*svgCell, size, i*svgCell)
+	}
+	fmt.Fprintf(buffer, `<rect x="0" y="0" width="%d" height="%d"/>`+"\n", size, size)
+	buffer.WriteString("</g>\n")
+
+	buffer.WriteString(`<g font-family="sans-serif" font-size="32" text-anchor="middle" dominant-baseline="central">` + "\n")
+	for i, val := range board {
+		if val == 0 {
+			continue
+		}
+		style := `fill="#1565c0"`
+		if isGiven(givens, board, i) {
+			style = `fill="#222" font-weight="bold"`
+		}
+		fmt.Fprintf(buffer, `<text x="%d" y="%d" %s>%d</text>`+"\n", (i%9)*svgCell+svgCell/2, (i/9)*svgCell+svgCell/2, style, val)
+	}
+	buffer.WriteString("</g>\n</svg>")
+	return buffer.Bytes()
+}
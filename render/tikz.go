@@ -0,0 +1,31 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Draws the board as a tikzpicture to include in a LaTeX document, which
+// needs \usepackage{tikz}. Cells are 1 unit wide, scale the picture to resize
+// it, ie. \begin{tikzpicture}[scale=0.5] is 4.5cm wide.
+func TikZ(board, givens sudoku.Board) []byte {
+	buffer := bytes.NewBufferString("")
+	buffer.WriteString("\\begin{tikzpicture}\n")
+	buffer.WriteString("  \\draw[gray!60, thin] (0,0) grid (9,9);\n")
+	buffer.WriteString("  \\draw[very thick, step=3] (0,0) grid (9,9);\n")
+	for i, val := range board {
+		if val == 0 {
+			continue
+		}
+		style := "blue!70!black"
+		if isGiven(givens, board, i) {
+			style = "font=\\bfseries"
+		}
+		// Rows count down from the top.
+		fmt.Fprintf(buffer, "  \\node[%s] at (%d.5,%d.5) {%d};\n", style, i%9, 8-i/9, val)
+	}
+	buffer.WriteString("\\end{tikzpicture}")
+	return buffer.Bytes()
+}
@@ -0,0 +1,262 @@
+package sudoku
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// A samurai sudoku (gattai-5), the top left, top right, center, bottom left
+// and bottom right 9x9 grids of a 21x21 layout. The center grid shares each
+// of its corner boxes with the inner corner box of another grid, and every
+// grid follows the classic rules.
+type Samurai [5]Board
+
+// The row and column of the top left cell of each grid in the layout.
+var samuraiOffsets = [5][2]int{{0, 0}, {0, 12}, {6, 6}, {12, 0}, {12, 12}}
+
+// The cell each cell of the grids is in, counting the cells of the layout
+// row by row, and the number of cells.
+var samuraiCells, samuraiCount = layoutSamurai()
+
+func layoutSamurai() ([5][81]int, int) {
+	index := map[int]int{}
+	for y := 0; y < 21; y++ {
+		for x := 0; x < 21; x++ {
+			for _, offset := range samuraiOffsets {
+				if y >= offset[0] && y < offset[0]+9 && x >= offset[1] && x < offset[1]+9 {
+					index[y*21+x] = len(index)
+					break
+				}
+			}
+		}
+	}
+
+	var cells [5][81]int
+	for grid, offset := range samuraiOffsets {
+		for pos := range cells[grid] {
+			cells[grid][pos] = index[(offset[0]+pos/9)*21+offset[1]+pos%9]
+		}
+	}
+	return cells, len(index)
+}
+
+// Parses a samurai sudoku, either as 21 rows of the layout with '.' or '0'
+// for blanks and spaces between the grids, or as a json array of the 5 grids
+// in any of the formats Parse reads (the text ones as json strings).
+func ParseSamurai(data []byte) (Samurai, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return Samurai{}, errors.New("No input")
+	}
+
+	var result Samurai
+	if data[0] == '[' {
+		grids := []json.RawMessage{}
+		if err := json.Unmarshal(data, &grids); err != nil {
+			return Samurai{}, err
+		}
+		if len(grids) != len(result) {
+			error := fmt.Sprintf("Samurai has %d grids, expected %d", len(grids), len(result))
+			return Samurai{}, errors.New(error)
+		}
+		for i, grid := range grids {
+			var text string
+			if json.Unmarshal(grid, &text) == nil {
+				grid = []byte(text)
+			}
+			board, err := Parse(grid)
+			if err != nil {
+				return Samurai{}, fmt.Errorf("Grid %d: %w", i+1, err)
+			}
+			result[i] = board
+		}
+	} else {
+		rows := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+		if len(rows) != 21 {
+			error := fmt.Sprintf("Samurai has %d rows, expected 21", len(rows))
+			return Samurai{}, errors.New(error)
+		}
+		layout := make([]byte, 21*21)
+		for y, row := range rows {
+			row = strings.TrimRight(row, " \r")
+			if len(row) > 21 {
+				error := fmt.Sprintf("Row %d is %d characters long, expected at most 21", y+1, len(row))
+				return Samurai{}, errors.New(error)
+			}
+			copy(layout[y*21:], row)
+		}
+		for i, offset := range samuraiOffsets {
+			result[i] = make(Board, 81)
+			for pos := range result[i] {
+				y, x := offset[0]+pos/9, offset[1]+pos%9
+				switch c := layout[y*21+x]; {
+				case c >= '1' && c <= '9':
+					result[i][pos] = int(c - '0')
+				case c != '.' && c != '0':
+					error := fmt.Sprintf("Invalid character at row %d, column %d: %q", y+1, x+1, c)
+					return Samurai{}, errors.New(error)
+				}
+			}
+		}
+	}
+
+	if _, err := result.IsValid(); err != nil {
+		return Samurai{}, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	return result, nil
+}
+
+// Returns true/false, and an error if a grid is not valid, or the grids
+// differ in the cells they share.
+func (p Samurai) IsValid() (bool, error) {
+	merged, err := p.merge()
+	if err != nil {
+		return false, err
+	}
+	for i, grid := range merged.split() {
+		if _, err := grid.IsValid(); err != nil {
+			return false, fmt.Errorf("Grid %d: %s", i+1, err)
+		}
+	}
+	return true, nil
+}
+
+// Returns the cells of the layout, or an error if a grid is not 9x9 or the
+// grids differ in a shared cell.
+func (p Samurai) merge() (Board, error) {
+	merged := make(Board, samuraiCount)
+	owner := make([]int, samuraiCount)
+	for i, grid := range p {
+		if len(grid) != 81 {
+			error := fmt.Sprintf("Grid %d has %d cells, expected 81", i+1, len(grid))
+			return nil, errors.New(error)
+		}
+		for pos, val := range grid {
+			cell := samuraiCells[i][pos]
+			if val != 0 && merged[cell] != 0 && merged[cell] != val {
+				error := fmt.Sprintf("Grid %d and grid %d differ at position: %d", owner[cell]+1, i+1, pos)
+				return nil, errors.New(error)
+			}
+			if val != 0 {
+				merged[cell] = val
+				owner[cell] = i
+			}
+		}
+	}
+	return merged, nil
+}
+
+// Returns the grids of the cells of the layout.
+func (b Board) split() Samurai {
+	var result Samurai
+	for i := range result {
+		result[i] = make(Board, 81)
+		for pos := range result[i] {
+			result[i][pos] = b[samuraiCells[i][pos]]
+		}
+	}
+	return result
+}
+
+// Solves all five grids together, returns the solved grids, or an error
+// wrapping ErrInvalidBoard or ErrUnsolvable if they cannot be solved.
+func (p Samurai) Solve() (Samurai, error) {
+	return p.SolveContext(context.Background())
+}
+
+// Like Solve, but gives up and returns the context's error as soon as ctx is
+// cancelled or its deadline is exceeded.
+func (p Samurai) SolveContext(ctx context.Context) (Samurai, error) {
+	var result Samurai
+	found := false
+	err := p.eachSolution(ctx, 1, func(solution Samurai) {
+		result = solution
+		found = true
+	})
+	if err != nil {
+		return Samurai{}, err
+	}
+	if !found {
+		return Samurai{}, ErrUnsolvable
+	}
+	return result, nil
+}
+
+// Counts the solutions, stopping once limit solutions have been found (a
+// limit of 0 or less counts all of them).
+func (p Samurai) CountSolutions(limit int) (int, error) {
+	count := 0
+	err := p.eachSolution(context.Background(), limit, func(Samurai) {
+		count++
+	})
+	return count, err
+}
+
+// Calls fn with each solution, searched with dancing links on the exact
+// cover problem of the whole layout.
+func (p Samurai) eachSolution(ctx context.Context, limit int, fn func(Samurai)) error {
+	if _, err := p.IsValid(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	merged, _ := p.merge()
+
+	// The rows and columns of each grid, then their boxes, the shared ones
+	// once.
+	s, _ := shapeOf(81)
+	units := make([][]int, samuraiCount)
+	boxes := map[int]int{}
+	for i := range p {
+		for pos, cell := range samuraiCells[i] {
+			units[cell] = append(units[cell], i*18+s.unit(pos, 0), i*18+s.unit(pos, 1))
+		}
+	}
+	for i := range p {
+		for box := 0; box < 9; box++ {
+			// Shared boxes have the same first cell.
+			first := samuraiCells[i][s.unitCells[s.unit(0, 2)+box][0]]
+			if _, ok := boxes[first]; ok {
+				continue
+			}
+			boxes[first] = len(p)*18 + len(boxes)
+			for _, pos := range s.unitCells[s.unit(0, 2)+box] {
+				cell := samuraiCells[i][pos]
+				units[cell] = append(units[cell], boxes[first])
+			}
+		}
+	}
+
+	count := 0
+	search := newCover(merged, 9, units, len(p)*18+len(boxes), func(int) bool {
+		return true
+	})
+	_, err := search.search(ctx, func(solution Board) bool {
+		fn(solution.split())
+		count++
+		return count == limit
+	})
+	return err
+}
+
+// Returns the 21 rows of the layout, with '.' for blanks and spaces between
+// the grids.
+func (p Samurai) String() string {
+	layout := bytes.Repeat([]byte(" "), 21*21)
+	for i, offset := range samuraiOffsets {
+		for pos, val := range p[i] {
+			c := byte('.')
+			if val != 0 {
+				c = byte('0' + val)
+			}
+			layout[(offset[0]+pos/9)*21+offset[1]+pos%9] = c
+		}
+	}
+	rows := []string{}
+	for y := 0; y < 21; y++ {
+		rows = append(rows, strings.TrimRight(string(layout[y*21:(y+1)*21]), " "))
+	}
+	return strings.Join(rows, "\n") + "\n"
+}
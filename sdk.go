@@ -0,0 +1,157 @@
+package sudoku
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A puzzle in the SadMan Sudoku .sdk format, ie:
+//
+//	#AJohn Doe
+//	#DA hard one
+//	[Puzzle]
+//	..3.2.6..
+//	9..3.5..1
+//	...
+//	[State]
+//	4832.76..
+//	...
+//
+// The metadata lines and the [Puzzle] and [State] sections are optional, a
+// file with just the 9 rows of givens is also valid.
+type SDK struct {
+	// The givens.
+	Puzzle Board
+	// The board as played so far, including the givens, or nil.
+	State Board
+	// The metadata keyed by its letter, ie. 'A' for the author, 'D' for the
+	// description, 'C' for a comment, 'B' for the date, 'S' for the source,
+	// 'L' for the level and 'U' for the url of the source.
+	Metadata map[byte]string
+}
+
+// Parses a .sdk file, returns an error if it is malformed or the puzzle or
+// state is not a valid board (wrapping ErrInvalidBoard).
+func ParseSDK(data []byte) (*SDK, error) {
+	sdk, err := readSDK(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := sdk.Puzzle.IsValid(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	if sdk.State != nil {
+		if _, err := sdk.State.IsValid(); err != nil {
+			return nil, fmt.Errorf("State: %w: %s", ErrInvalidBoard, err)
+		}
+	}
+	return sdk, nil
+}
+
+// Parses a .sdk file without validating the boards.
+func readSDK(data []byte) (*SDK, error) {
+	sdk := &SDK{Metadata: map[byte]string{}}
+	sections := map[string][]string{}
+	section := "[Puzzle]"
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case line[0] == '#':
+			if err := parseMetadata(line, sdk.Metadata); err != nil {
+				return nil, err
+			}
+		case line[0] == '[':
+			section = line
+			if section != "[Puzzle]" && section != "[State]" {
+				return nil, errors.New("Unknown section: " + section)
+			}
+		default:
+			sections[section] = append(sections[section], line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	sdk.Puzzle, err = parseRows(sections["[Puzzle]"])
+	if err != nil {
+		return nil, err
+	}
+	if rows, ok := sections["[State]"]; ok {
+		sdk.State, err = parseRows(rows)
+		if err != nil {
+			return nil, fmt.Errorf("State: %w", err)
+		}
+	}
+	return sdk, nil
+}
+
+// Parses 9 rows of 9 characters in the line format.
+func parseRows(rows []string) (Board, error) {
+	if len(rows) != 9 {
+		error := fmt.Sprintf("Expected 9 rows, got %d", len(rows))
+		return nil, errors.New(error)
+	}
+	for y, row := range rows {
+		if len(row) != 9 {
+			error := fmt.Sprintf("Row %d does not have 9 characters", y+1)
+			return nil, errors.New(error)
+		}
+	}
+	return parseLine(strings.Join(rows, ""))
+}
+
+// Returns the puzzle in the .sdk format, with the metadata in alphabetical
+// order.
+func (s *SDK) String() string {
+	buffer := bytes.NewBufferString("")
+	writeMetadata(buffer, s.Metadata)
+	if s.State != nil {
+		buffer.WriteString("[Puzzle]\n")
+	}
+	writeRows(buffer, s.Puzzle)
+	if s.State != nil {
+		buffer.WriteString("[State]\n")
+		writeRows(buffer, s.State)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// Writes the board as 9 rows in the line format.
+func writeRows(buffer *bytes.Buffer, b Board) {
+	line := b.Line()
+	for y := 0; y < 9; y++ {
+		buffer.WriteString(line[y*9 : y*9+9])
+		buffer.WriteString("\n")
+	}
+}
+
+// Parses a "#A..." metadata line into metadata.
+func parseMetadata(line string, metadata map[byte]string) error {
+	if len(line) < 2 {
+		return errors.New("Metadata line without a letter")
+	}
+	metadata[line[1]] = strings.TrimSpace(line[2:])
+	return nil
+}
+
+// Writes the metadata lines in alphabetical order.
+func writeMetadata(buffer *bytes.Buffer, metadata map[byte]string) {
+	letters := make([]int, 0, len(metadata))
+	for letter := range metadata {
+		letters = append(letters, int(letter))
+	}
+	sort.Ints(letters)
+	for _, letter := range letters {
+		fmt.Fprintf(buffer, "#%c%s\n", letter, metadata[byte(letter)])
+	}
+}
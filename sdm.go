@@ -0,0 +1,61 @@
+package sudoku
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A collection of puzzles in the .sdm format, an 81 character line per
+// puzzle, optionally preceded by metadata lines as in the .sdk format (see
+// SDK), ie:
+//
+//	#DDaily puzzles
+//	..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..
+//	2...8.3...6..7..84.3.5..2.9...1.54.8.........4.27.6...3.1..7.4.72..4..6...4.1...3
+type SDM struct {
+	Puzzles []Board
+	// The metadata of the collection, keyed by its letter as for SDK.
+	Metadata map[byte]string
+}
+
+// Parses a .sdm file, returns an error with the line number if a line is
+// malformed or not a valid board (wrapping ErrInvalidBoard).
+func ParseSDM(data []byte) (*SDM, error) {
+	sdm := &SDM{Metadata: map[byte]string{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "":
+			continue
+		case text[0] == '#':
+			if err := parseMetadata(text, sdm.Metadata); err != nil {
+				return nil, fmt.Errorf("Line %d: %w", line, err)
+			}
+		default:
+			board, err := ParseLine(text)
+			if err != nil {
+				return nil, fmt.Errorf("Line %d: %w", line, err)
+			}
+			sdm.Puzzles = append(sdm.Puzzles, board)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sdm, nil
+}
+
+// Returns the collection in the .sdm format, with the metadata in
+// alphabetical order.
+func (s *SDM) String() string {
+	buffer := bytes.NewBufferString("")
+	writeMetadata(buffer, s.Metadata)
+	for _, puzzle := range s.Puzzles {
+		buffer.WriteString(puzzle.Line())
+		buffer.WriteString("\n")
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// The quota of an api key, see Server.APIKeys.
+type APIKey struct {
+	// The requests per second allowed with the key, 0 to use the server's
+	// RateLimit.
+	RateLimit float64
+	// The requests that can be made at once with the key, 0 to use the
+	// server's RateBurst.
+	RateBurst int
+}
+
+// Parses an api keys file, with a key per line optionally followed by its
+// requests per second and burst, ie. "3f9a2c 5 10". Blank lines and lines
+// starting with # are ignored.
+func ParseAPIKeys(data []byte) (map[string]APIKey, error) {
+	keys := map[string]APIKey{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) > 3 {
+			return nil, fmt.Errorf("Line %d: expected a key, a rate and a burst", line)
+		}
+
+		var quota APIKey
+		var err error
+		if len(fields) > 1 {
+			quota.RateLimit, err = strconv.ParseFloat(fields[1], 64)
+			if err != nil || quota.RateLimit < 0 {
+				return nil, fmt.Errorf("Line %d: invalid rate: %s", line, fields[1])
+			}
+		}
+		if len(fields) > 2 {
+			quota.RateBurst, err = strconv.Atoi(fields[2])
+			if err != nil || quota.RateBurst < 0 {
+				return nil, fmt.Errorf("Line %d: invalid burst: %s", line, fields[2])
+			}
+		}
+		if _, ok := keys[fields[0]]; ok {
+			return nil, fmt.Errorf("Line %d: key is given twice", line)
+		}
+		keys[fields[0]] = quota
+	}
+	return keys, scanner.Err()
+}
+
+// Returns the api key in the request's Authorization header, with or without
+// the "Bearer " scheme.
+func apiKey(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return strings.TrimSpace(header)
+}
+
+// Answers with 401 Unauthorized unless the request has one of the server's
+// api keys, if it has any.
+func (s *Server) authenticate(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.APIKeys != nil {
+			if _, ok := s.APIKeys[apiKey(r)]; !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, r, http.StatusUnauthorized, errors.New("Missing or unknown api key"))
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
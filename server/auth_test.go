@@ -0,0 +1,82 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		authorization string
+		status        int
+	}{
+		{"bearer", "/validate", "Bearer 3f9a2c", http.StatusOK},
+		{"lowercase bearer", "/validate", "bearer 3f9a2c", http.StatusOK},
+		{"without a scheme", "/validate", "3f9a2c", http.StatusOK},
+		{"missing", "/validate", "", http.StatusUnauthorized},
+		{"unknown", "/validate", "Bearer 000000", http.StatusUnauthorized},
+		{"partial", "/validate", "Bearer 3f9a", http.StatusUnauthorized},
+		{"daily", "/daily", "", http.StatusUnauthorized},
+		{"outside the api", "/healthz", "", http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Server{APIKeys: map[string]APIKey{"3f9a2c": {}}}
+			method := "POST"
+			if test.target != "/validate" {
+				method = "GET"
+			}
+			headers := map[string]string{}
+			if test.authorization != "" {
+				headers["Authorization"] = test.authorization
+			}
+			response := serve(s, method, test.target, easyLine, headers)
+			if response.Code != test.status {
+				t.Fatalf("got status %d, want %d: %s", response.Code, test.status, response.Body)
+			}
+			if challenge := response.Header().Get("WWW-Authenticate"); (challenge == "Bearer") != (test.status == http.StatusUnauthorized) {
+				t.Errorf("got WWW-Authenticate %q with status %d", challenge, response.Code)
+			}
+		})
+	}
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		keys map[string]APIKey
+	}{
+		{"empty", "", map[string]APIKey{}},
+		{"keys", "# The keys\n3f9a2c\n\n7b1e4d 5\n9c0d2a 0.5 10\n", map[string]APIKey{
+			"3f9a2c": {},
+			"7b1e4d": {RateLimit: 5},
+			"9c0d2a": {RateLimit: 0.5, RateBurst: 10},
+		}},
+		{"too many fields", "3f9a2c 5 10 20", nil},
+		{"bad rate", "3f9a2c fast", nil},
+		{"negative rate", "3f9a2c -1", nil},
+		{"bad burst", "3f9a2c 5 many", nil},
+		{"twice", "3f9a2c\n3f9a2c 5", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keys, err := ParseAPIKeys([]byte(test.data))
+			if test.keys == nil {
+				if err == nil {
+					t.Errorf("got %v, want an error", keys)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(keys, test.keys) {
+				t.Errorf("got %v, want %v", keys, test.keys)
+			}
+		})
+	}
+}
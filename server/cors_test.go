@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  *Server
+		method  string
+		headers map[string]string
+		status  int
+		// The Access-Control-Allow-Origin header, if any.
+		origin string
+	}{
+		{"no origin", &Server{AllowedOrigins: []string{"https://example.com"}},
+			"POST", nil, http.StatusOK, ""},
+		{"allowed", &Server{AllowedOrigins: []string{"https://example.com"}},
+			"POST", map[string]string{"Origin": "https://example.com"}, http.StatusOK, "https://example.com"},
+		{"not allowed", &Server{AllowedOrigins: []string{"https://example.com"}},
+			"POST", map[string]string{"Origin": "https://example.org"}, http.StatusOK, ""},
+		{"none allowed", &Server{},
+			"POST", map[string]string{"Origin": "https://example.com"}, http.StatusOK, ""},
+		{"any", &Server{AllowedOrigins: []string{"*"}},
+			"POST", map[string]string{"Origin": "https://example.org"}, http.StatusOK, "https://example.org"},
+		{"preflight", &Server{AllowedOrigins: []string{"https://example.com"}},
+			"OPTIONS", map[string]string{"Origin": "https://example.com", "Access-Control-Request-Method": "POST"}, http.StatusNoContent, "https://example.com"},
+		{"preflight without a key", &Server{AllowedOrigins: []string{"https://example.com"}, APIKeys: map[string]APIKey{"3f9a2c": {}}},
+			"OPTIONS", map[string]string{"Origin": "https://example.com", "Access-Control-Request-Method": "POST"}, http.StatusNoContent, "https://example.com"},
+		{"preflight not allowed", &Server{AllowedOrigins: []string{"https://example.com"}},
+			"OPTIONS", map[string]string{"Origin": "https://example.org", "Access-Control-Request-Method": "POST"}, http.StatusMethodNotAllowed, ""},
+		{"options without a method", &Server{AllowedOrigins: []string{"https://example.com"}},
+			"OPTIONS", map[string]string{"Origin": "https://example.com"}, http.StatusMethodNotAllowed, "https://example.com"},
+		{"error without a key", &Server{AllowedOrigins: []string{"https://example.com"}, APIKeys: map[string]APIKey{"3f9a2c": {}}},
+			"POST", map[string]string{"Origin": "https://example.com"}, http.StatusUnauthorized, "https://example.com"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := serve(test.server, test.method, "/validate", easyLine, test.headers)
+			if response.Code != test.status {
+				t.Fatalf("got status %d, want %d: %s", response.Code, test.status, response.Body)
+			}
+			if origin := response.Header().Get("Access-Control-Allow-Origin"); origin != test.origin {
+				t.Errorf("got Access-Control-Allow-Origin %q, want %q", origin, test.origin)
+			}
+			if vary := response.Header().Get("Vary"); (vary == "Origin") != (test.origin != "") {
+				t.Errorf("got Vary %q", vary)
+			}
+			preflight := response.Header().Get("Access-Control-Allow-Methods") != ""
+			if preflight != (test.status == http.StatusNoContent) {
+				t.Errorf("got Access-Control-Allow-Methods %q", response.Header().Get("Access-Control-Allow-Methods"))
+			}
+		})
+	}
+}
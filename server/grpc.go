@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/dhedegaard/sudoku.go"
+	sudokupb "github.com/dhedegaard/sudoku.go/proto"
+)
+
+// Returns a gRPC server serving the sudoku.v1.Sudoku service, see
+// proto/sudoku.proto.
+func (s *Server) GRPCServer() *grpc.Server {
+	g := grpc.NewServer()
+	sudokupb.RegisterSudokuServer(g, &grpcService{server: s})
+	return g
+}
+
+// Implements the gRPC service on top of a Server.
+type grpcService struct {
+	sudokupb.UnimplementedSudokuServer
+	server *Server
+}
+
+// Returns the gRPC status for an error from the sudoku package.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, sudoku.ErrInvalidBoard):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, sudoku.ErrUnsolvable), errors.Is(err, sudoku.ErrNotUnique),
+		errors.Is(err, sudoku.ErrDifficultyNotReached):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.FromContextError(err).Err()
+	}
+}
+
+// Solves a board within the server's timeout.
+func (g *grpcService) solve(ctx context.Context, board *sudokupb.Board) (sudoku.Board, error) {
+	return g.server.solveBoard(ctx, board.Sudoku(), sudoku.Variant{}, nil)
+}
+
+func (g *grpcService) Solve(ctx context.Context, req *sudokupb.SolveRequest) (*sudokupb.SolveResponse, error) {
+	solution, err := g.solve(ctx, req.GetBoard())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &sudokupb.SolveResponse{Solution: sudokupb.NewBoard(solution), Id: req.GetId()}, nil
+}
+
+func (g *grpcService) SolveStream(stream sudokupb.Sudoku_SolveStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &sudokupb.SolveResponse{Id: req.GetId()}
+		solution, err := g.solve(stream.Context(), req.GetBoard())
+		if err != nil {
+			resp.Error = err.Error()
+		} else {
+			resp.Solution = sudokupb.NewBoard(solution)
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (g *grpcService) Validate(ctx context.Context, req *sudokupb.ValidateRequest) (*sudokupb.ValidateResponse, error) {
+	resp := &sudokupb.ValidateResponse{Valid: true}
+	if _, err := req.GetBoard().Sudoku().IsValid(); err != nil {
+		resp.Valid = false
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+func (g *grpcService) Generate(ctx context.Context, req *sudokupb.GenerateRequest) (*sudokupb.GenerateResponse, error) {
+	options := sudoku.GenerateOptions{Seed: rand.Int63()}
+	if req.Seed != nil {
+		options.Seed = req.GetSeed()
+	}
+	switch req.GetSymmetry() {
+	case sudokupb.Symmetry_SYMMETRY_NONE:
+		options.Symmetry = sudoku.SymmetryNone
+	case sudokupb.Symmetry_SYMMETRY_ROTATIONAL:
+		options.Symmetry = sudoku.SymmetryRotational
+	case sudokupb.Symmetry_SYMMETRY_MIRROR:
+		options.Symmetry = sudoku.SymmetryMirror
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown symmetry: %d", req.GetSymmetry())
+	}
+	puzzle, err := g.server.generatePuzzle(ctx, options)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &sudokupb.GenerateResponse{Puzzle: sudokupb.NewBoard(puzzle.Board)}, nil
+}
+
+func (g *grpcService) Rate(ctx context.Context, req *sudokupb.RateRequest) (*sudokupb.RateResponse, error) {
+	ctx, cancel := g.server.withTimeout(ctx)
+	defer cancel()
+	rating, err := req.GetBoard().Sudoku().RateContext(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &sudokupb.RateResponse{
+		Score:      int32(rating.Score),
+		Difficulty: rating.Difficulty.String(),
+		Technique:  rating.Technique,
+	}, nil
+}
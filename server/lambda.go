@@ -0,0 +1,80 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Handles an API Gateway proxy event with the json api, so the server can run
+// as an AWS Lambda function, ie. with lambda.Start(s.Lambda). The event's path
+// and query string select the endpoint and its options, like the request's
+// would.
+func (s *Server) Lambda(ctx context.Context, event events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	body := []byte(event.Body)
+	if event.IsBase64Encoded {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(event.Body)
+		if err != nil {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusBadRequest}, nil
+		}
+	}
+
+	if event.Path == "" || event.Path[0] != '/' {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusBadRequest}, nil
+	}
+	query := url.Values{}
+	for key, values := range event.MultiValueQueryStringParameters {
+		query[key] = values
+	}
+	for key, value := range event.QueryStringParameters {
+		if _, ok := query[key]; !ok {
+			query.Set(key, value)
+		}
+	}
+	target := &url.URL{Path: event.Path, RawQuery: query.Encode()}
+	request, err := http.NewRequestWithContext(ctx, event.HTTPMethod, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusBadRequest}, nil
+	}
+	for key, values := range event.MultiValueHeaders {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	for key, value := range event.Headers {
+		if request.Header.Get(key) == "" {
+			request.Header.Set(key, value)
+		}
+	}
+	if ip := event.RequestContext.Identity.SourceIP; ip != "" {
+		request.RemoteAddr = net.JoinHostPort(ip, "0")
+	}
+
+	s.lambdaOnce.Do(func() {
+		s.lambdaHandler = s.Handler()
+	})
+	recorder := httptest.NewRecorder()
+	s.lambdaHandler.ServeHTTP(recorder, request)
+
+	response := events.APIGatewayProxyResponse{
+		StatusCode: recorder.Code,
+		Headers:    map[string]string{},
+		Body:       recorder.Body.String(),
+	}
+	for key := range recorder.Header() {
+		response.Headers[key] = recorder.Header().Get(key)
+	}
+	// Binary bodies must be base64 encoded for API Gateway.
+	if isMsgpack(recorder.Header().Get("Content-Type")) {
+		response.Body = base64.StdEncoding.EncodeToString(recorder.Body.Bytes())
+		response.IsBase64Encoded = true
+	}
+	return response, nil
+}
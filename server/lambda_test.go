@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestLambda(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+	tests := []struct {
+		name   string
+		event  events.APIGatewayProxyRequest
+		status int
+	}{
+		{"empty path", events.APIGatewayProxyRequest{HTTPMethod: "GET"}, http.StatusBadRequest},
+		{"relative path", events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "healthz"}, http.StatusBadRequest},
+		{"bad method", events.APIGatewayProxyRequest{HTTPMethod: "G T", Path: "/healthz"}, http.StatusBadRequest},
+		{"bad base64", events.APIGatewayProxyRequest{HTTPMethod: "POST", Path: "/solve", Body: "!", IsBase64Encoded: true}, http.StatusBadRequest},
+		{"health", events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/healthz"}, http.StatusOK},
+		{"solve", events.APIGatewayProxyRequest{
+			HTTPMethod: "POST",
+			Path:       "/solve",
+			Body:       `{"board":"..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."}`,
+		}, http.StatusOK},
+		{"query", events.APIGatewayProxyRequest{
+			HTTPMethod:            "GET",
+			Path:                  "/daily",
+			QueryStringParameters: map[string]string{"date": today, "difficulty": "nonsense"},
+		}, http.StatusBadRequest},
+		{"multi value query", events.APIGatewayProxyRequest{
+			HTTPMethod:                      "GET",
+			Path:                            "/daily",
+			MultiValueQueryStringParameters: map[string][]string{"date": {"2024-13-01"}},
+		}, http.StatusBadRequest},
+		{"multi value headers", events.APIGatewayProxyRequest{
+			HTTPMethod:        "POST",
+			Path:              "/solve",
+			MultiValueHeaders: map[string][]string{"Content-Type": {"application/msgpack"}},
+			Body:              `{"board":"..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."}`,
+		}, http.StatusBadRequest},
+	}
+	s := &Server{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response, err := s.Lambda(context.Background(), test.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.StatusCode != test.status {
+				t.Errorf("got status %d, want %d: %s", response.StatusCode, test.status, response.Body)
+			}
+		})
+	}
+}
+
+func TestLambdaSourceIP(t *testing.T) {
+	s := &Server{RateLimit: 1, RateBurst: 1}
+	request := func(ip string) int {
+		event := events.APIGatewayProxyRequest{HTTPMethod: "POST", Path: "/validate", Body: `{"board":"` + emptyLine + `"}`}
+		event.RequestContext.Identity.SourceIP = ip
+		response, err := s.Lambda(context.Background(), event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response.StatusCode
+	}
+	if status := request("192.0.2.1"); status != http.StatusOK {
+		t.Fatalf("got status %d for the first request", status)
+	}
+	if status := request("192.0.2.1"); status != http.StatusTooManyRequests {
+		t.Errorf("got status %d for the second request from the same ip, want 429", status)
+	}
+	if status := request("192.0.2.2"); status != http.StatusOK {
+		t.Errorf("got status %d for a request from another ip, want 200", status)
+	}
+}
+
+const emptyLine = "................................................................................."
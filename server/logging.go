@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bufio"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Records the status of a response for the request log.
+type loggedResponse struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *loggedResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *loggedResponse) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Takes over the connection for the websocket.
+func (w *loggedResponse) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.status = http.StatusSwitchingProtocols
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Returns the wrapped response, for http.ResponseController.
+func (w *loggedResponse) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Logs every request to s.Logger once it is answered: at info level, or
+// warn level if it failed on the server's side.
+func (s *Server) logRequests(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		response := &loggedResponse{ResponseWriter: w}
+		handler.ServeHTTP(response, r)
+		if response.status == 0 {
+			response.status = http.StatusOK
+		}
+		level := slog.LevelInfo
+		if response.status >= 500 {
+			level = slog.LevelWarn
+		}
+		s.Logger.Log(r.Context(), level, "Request", "method", r.Method, "path", r.URL.Path,
+			"status", response.status, "duration", time.Since(start), "remote", r.RemoteAddr)
+	})
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The metrics served at /metrics, shared by every Server in the process.
+var (
+	solvesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "sudoku_solves_total",
+		Help: "Boards solved, by result: solved, invalid, unsolvable, timeout or error.",
+	}, []string{"result"})
+	solveDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "sudoku_solve_duration_seconds",
+		Help:    "Time spent solving a board.",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 4, 10),
+	})
+	solveBacktracks = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "sudoku_solve_backtracks",
+		Help:    "Guesses taken back while solving a board, see sudoku.Stats.",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 12),
+	})
+	generatedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "sudoku_generated_total",
+		Help: "Puzzles generated.",
+	})
+	generateDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "sudoku_generate_duration_seconds",
+		Help:    "Time spent generating a puzzle.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	})
+)
+
+// Solves a board under the variant's rules within the server's timeout and
+// records it in the metrics. observe is passed on to the solver, if not nil.
+func (s *Server) solveBoard(ctx context.Context, board sudoku.Board, variant sudoku.Variant, observe func(sudoku.Event)) (sudoku.Board, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	// Count the backtracks in the solver's stats, the remove events also
+	// undo the values filled in from the guesses.
+	var stats sudoku.Stats
+	solver := s.Solver
+	solver.Variant = variant
+	solver.Observe = observe
+	solver.Stats = &stats
+	if solver.Logger == nil {
+		solver.Logger = s.Logger
+	}
+
+	start := time.Now()
+	solution, err := solver.Solve(ctx, board)
+	solveDuration.Observe(time.Since(start).Seconds())
+	solveBacktracks.Observe(float64(stats.Backtracks))
+	solvesTotal.WithLabelValues(solveResult(err)).Inc()
+	return solution, err
+}
+
+// Returns the result label of a solve.
+func solveResult(err error) string {
+	switch {
+	case err == nil:
+		return "solved"
+	case errors.Is(err, sudoku.ErrInvalidBoard):
+		return "invalid"
+	case errors.Is(err, sudoku.ErrUnsolvable):
+		return "unsolvable"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
+// Generates a puzzle within the server's timeout and records it in the
+// metrics.
+func (s *Server) generatePuzzle(ctx context.Context, options sudoku.GenerateOptions) (sudoku.Puzzle, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	start := time.Now()
+	puzzle, err := sudoku.GeneratePuzzleContext(ctx, options)
+	if err != nil {
+		return puzzle, err
+	}
+	generateDuration.Observe(time.Since(start).Seconds())
+	generatedTotal.Inc()
+	return puzzle, nil
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Returns the value of a metric without labels from /metrics.
+func metricValue(t *testing.T, s *Server, name string) float64 {
+	body := serve(s, "GET", "/metrics", "", nil).Body.String()
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == name {
+			value, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return value
+		}
+	}
+	t.Fatalf("no metric %s", name)
+	return 0
+}
+
+func TestSolveBacktracks(t *testing.T) {
+	const hard = "1.......2.9.4...5...6...7...5.9.3.......7.......85..4.7.....6...3...9.8...2.....1"
+	board, err := sudoku.ParseLine(hard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats sudoku.Stats
+	removed := 0
+	solver := sudoku.Solver{Stats: &stats, Observe: func(event sudoku.Event) {
+		if event.Kind == sudoku.Remove {
+			removed++
+		}
+	}}
+	if _, err := solver.Solve(context.Background(), board); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Backtracks == 0 || stats.Backtracks >= removed {
+		t.Fatalf("got %d backtracks and %d values removed, want fewer backtracks", stats.Backtracks, removed)
+	}
+
+	s := &Server{}
+	before := metricValue(t, s, "sudoku_solve_backtracks_sum")
+	if response := serve(s, "POST", "/solve", `{"board":"`+hard+`"}`, nil); response.Code != 200 {
+		t.Fatalf("got status %d: %s", response.Code, response.Body)
+	}
+	if got := metricValue(t, s, "sudoku_solve_backtracks_sum") - before; got != float64(stats.Backtracks) {
+		t.Errorf("got %v backtracks, want %d", got, stats.Backtracks)
+	}
+}
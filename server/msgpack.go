@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// The content types of MessagePack, all are accepted and the first is used
+// for responses.
+var msgpackTypes = []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}
+
+// Returns true if the media type is one of the MessagePack types.
+func isMsgpack(mediaType string) bool {
+	for _, t := range msgpackTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the request's Accept header prefers MessagePack over json,
+// json is preferred on ties and when there is no Accept header.
+func acceptsMsgpack(r *http.Request) bool {
+	jsonQuality, msgpackQuality := 0.0, 0.0
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(accepted)
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			quality = q
+		}
+		switch {
+		case isMsgpack(mediaType):
+			msgpackQuality = max(msgpackQuality, quality)
+		case mediaType == "application/json" || mediaType == "application/*" || mediaType == "*/*":
+			jsonQuality = max(jsonQuality, quality)
+		}
+	}
+	return msgpackQuality > jsonQuality
+}
+
+// Converts a MessagePack request body to the json it stands for, so it is
+// parsed like a json body. A string is returned as is, so a board can be sent
+// as its 81 character line.
+func msgpackToJSON(body []byte) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+	decoder := msgpack.NewDecoder(bytes.NewReader(body))
+	value, err := decoder.DecodeInterface()
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(value)
+}
+
+// Encodes value as MessagePack, with the same structure as its json, ie.
+// with the field names of its json tags and text for types encoded as text.
+func encodeMsgpack(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	buffer := bytes.NewBuffer(nil)
+	encoder := msgpack.NewEncoder(buffer)
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(jsonNumbers(decoded)); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Replaces the json.Numbers in a decoded json value with integers, or floats
+// if they have a fraction.
+func jsonNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = jsonNumbers(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = jsonNumbers(v[key])
+		}
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// How long a client's bucket is kept after its last request.
+const limiterExpiry = 10 * time.Minute
+
+// A token bucket per client.
+type limiter struct {
+	mutex   sync.Mutex
+	clients map[string]*client
+	// When expired buckets were last removed.
+	swept time.Time
+}
+
+type client struct {
+	bucket *rate.Limiter
+	seen   time.Time
+}
+
+// Returns the bucket of the client with the given key, creating it with
+// the server's limits if needed.
+func (l *limiter) bucket(key string, limit rate.Limit, burst int) *rate.Limiter {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.swept) > limiterExpiry {
+		for k, c := range l.clients {
+			if now.Sub(c.seen) > limiterExpiry {
+				delete(l.clients, k)
+			}
+		}
+		l.swept = now
+	}
+
+	if l.clients == nil {
+		l.clients = map[string]*client{}
+	}
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{bucket: rate.NewLimiter(limit, burst)}
+		l.clients[key] = c
+	}
+	c.seen = now
+	return c.bucket
+}
+
+// Returns the key a client is rate limited by and its limits. Clients are
+// limited by their api key if it is one of the server's keys and has its own
+// limits, or the server limits by key. Other clients, including those
+// sending an unknown key, are limited by their ip address, so making up keys
+// does not get a client fresh buckets.
+func (s *Server) clientLimit(r *http.Request) (string, float64, int) {
+	key := apiKey(r)
+	if quota, ok := s.APIKeys[key]; ok {
+		if quota.RateLimit > 0 {
+			return "key " + key, quota.RateLimit, quota.RateBurst
+		}
+		if s.RateByKey {
+			return "key " + key, s.RateLimit, s.RateBurst
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip " + host, s.RateLimit, s.RateBurst
+}
+
+// Answers with 429 Too Many Requests when the client has used up its
+// requests, if it has a rate limit.
+func (s *Server) limit(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, limit, burst := s.clientLimit(r)
+		if limit <= 0 {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		if burst < 1 {
+			burst = int(math.Ceil(limit))
+		}
+		reservation := s.limiter.bucket(key, rate.Limit(limit), burst).Reserve()
+		if delay := reservation.Delay(); delay > 0 {
+			reservation.Cancel()
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			writeError(w, r, http.StatusTooManyRequests, errors.New("Too many requests"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// A request to the api from a client.
+type clientRequest struct {
+	// The ip address of the client.
+	ip string
+	// The api key sent, if any.
+	key    string
+	status int
+}
+
+func TestRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   *Server
+		requests []clientRequest
+	}{
+		{"no limit", &Server{}, []clientRequest{
+			{"192.0.2.1", "", http.StatusOK},
+			{"192.0.2.1", "", http.StatusOK},
+			{"192.0.2.1", "", http.StatusOK},
+		}},
+		{"burst", &Server{RateLimit: 0.01, RateBurst: 2}, []clientRequest{
+			{"192.0.2.1", "", http.StatusOK},
+			{"192.0.2.1", "", http.StatusOK},
+			{"192.0.2.1", "", http.StatusTooManyRequests},
+			{"192.0.2.2", "", http.StatusOK},
+		}},
+		{"burst from the rate", &Server{RateLimit: 1.5}, []clientRequest{
+			{"192.0.2.1", "", http.StatusOK},
+			{"192.0.2.1", "", http.StatusOK},
+			{"192.0.2.1", "", http.StatusTooManyRequests},
+		}},
+		{"keys by ip", &Server{RateLimit: 0.01, RateBurst: 1, APIKeys: map[string]APIKey{"a": {}, "b": {}}}, []clientRequest{
+			{"192.0.2.1", "a", http.StatusOK},
+			{"192.0.2.1", "b", http.StatusTooManyRequests},
+			{"192.0.2.2", "a", http.StatusOK},
+		}},
+		{"rate by key", &Server{RateLimit: 0.01, RateBurst: 1, RateByKey: true, APIKeys: map[string]APIKey{"a": {}, "b": {}}}, []clientRequest{
+			{"192.0.2.1", "a", http.StatusOK},
+			{"192.0.2.1", "b", http.StatusOK},
+			{"192.0.2.2", "a", http.StatusTooManyRequests},
+		}},
+		{"key with its own limit", &Server{RateLimit: 100, APIKeys: map[string]APIKey{"a": {RateLimit: 0.01, RateBurst: 1}, "b": {}}}, []clientRequest{
+			{"192.0.2.1", "a", http.StatusOK},
+			{"192.0.2.2", "a", http.StatusTooManyRequests},
+			{"192.0.2.1", "b", http.StatusOK},
+		}},
+		{"unknown key", &Server{RateLimit: 0.01, RateBurst: 1, APIKeys: map[string]APIKey{"a": {}}}, []clientRequest{
+			{"192.0.2.1", "c", http.StatusUnauthorized},
+			{"192.0.2.1", "a", http.StatusOK},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			handler := test.server.Handler()
+			for i, c := range test.requests {
+				request := httptest.NewRequest("POST", "/validate", strings.NewReader(easyLine))
+				request.RemoteAddr = c.ip + ":1234"
+				if c.key != "" {
+					request.Header.Set("Authorization", "Bearer "+c.key)
+				}
+				response := httptest.NewRecorder()
+				handler.ServeHTTP(response, request)
+				if response.Code != c.status {
+					t.Fatalf("got status %d for request %d, want %d: %s", response.Code, i+1, c.status, response.Body)
+				}
+				if retry := response.Header().Get("Retry-After"); (retry != "") != (c.status == http.StatusTooManyRequests) {
+					t.Errorf("got Retry-After %q for request %d", retry, i+1)
+				}
+			}
+		})
+	}
+}
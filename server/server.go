@@ -0,0 +1,604 @@
+/* Package server serves the sudoku solver over http.
+ *
+ * Every endpoint takes a POST with a json body and answers with json. Boards
+ * are accepted in any format sudoku.Parse understands (a json array of 81
+ * numbers, 9 nested rows or an 81 character string), and returned as a json
+ * array of 81 numbers. Failures are answered with {"error":"..."}, and the
+ * problems of the board as for /validate if it is not valid.
+ *
+ * Bodies can be sent as MessagePack instead, with a Content-Type of
+ * application/msgpack, and answers are MessagePack when the Accept header
+ * prefers application/msgpack over json.
+ *
+ *   POST /solve     board in, solved board out.
+ *   POST /validate  board in, {"valid":true} or {"valid":false,"error":"...",
+ *                   "problems":[...]} out, with every problem of the board
+ *                   (see sudoku.ValidationReport), ie.
+ *                   {"cells":[{"row":0,"column":0},{"row":0,"column":4}],
+ *                   "message":"Number 5 appears twice in row 1"}.
+ *   POST /generate  optional sudoku.GenerateOptions in, ie.
+ *                   {"seed":1,"symmetry":"rotational","difficulty":"hard"}
+ *                   or {"size":6,"variant":{"diagonals":true}} for a 6x6
+ *                   sudoku X, puzzle out. With {"variant":{"alldots":true}}
+ *                   the puzzle is a kropki sudoku, written as a
+ *                   sudoku.Puzzle with the dots.
+ *   POST /rate      board in, sudoku.Rating out.
+ *   POST /hint      partial board in, the next sudoku.Step out.
+ *   GET /daily      the puzzle of the day out, the same for every client,
+ *                   see sudoku.DailyOptions. Takes ?date=2024-06-01 (today
+ *                   in UTC by default) and ?difficulty=hard.
+ *
+ * /solve and /validate also take a board with the rules of a variant, see
+ * sudoku.Variant, ie. {"board":"...","regions":[0,0,0,1,...]} for jigsaw
+ * sudoku, {"cages":[{"cells":[0,1],"sum":3},...]} for killer sudoku or
+ * {"x":[[0,1],...],"v":[[12,21],...]} for XV sudoku.
+ *
+ * /solve, /validate and /hint also take the notes of a game client along
+ * with the board, see sudoku.Notes, ie.
+ * {"board":"...","pencilmarks":[[1,2],null,...],"marks":["red","",...]},
+ * and answer with them so the client keeps its state: /solve with a
+ * sudoku.Puzzle of the solved board and the notes, /validate and /hint with
+ * the notes added to their answer.
+ *
+ * The search can also be followed as it happens, over a websocket:
+ *
+ *   GET /ws/solve   send a board as the first message, receive a message per
+ *                   value placed or removed, ie.
+ *                   {"type":"place","cell":3,"value":7}, and finally
+ *                   {"type":"solution","board":[...]} or
+ *                   {"type":"error","error":"..."}. An optional ?delay= in
+ *                   milliseconds pauses after each event.
+ *
+ * With Server.APIKeys, the endpoints above answer 401 unless the request has
+ * one of the keys in its Authorization header.
+ *
+ * With a Server.RateLimit, each client gets a token bucket for the endpoints
+ * above, and is answered with a 429 and a Retry-After header when it is
+ * empty.
+ *
+ * Browser apps on other sites can call the endpoints above if their origin
+ * is in Server.AllowedOrigins.
+ *
+ * Metrics about the solves and generated puzzles are served for Prometheus:
+ *
+ *   GET /metrics
+ *
+ * And for health checks, ie. probes in Kubernetes:
+ *
+ *   GET /healthz    {"status":"ok"} while the server is running.
+ *   GET /readyz     {"status":"ready"} once Server.WarmUp is done, until then
+ *                   a 503 with {"status":"warming up"}.
+ *
+ * With Server.Profiling, the profiles of net/http/pprof are served too, ie.
+ * to capture a cpu profile while hard puzzles are being solved:
+ *
+ *   GET /debug/pprof/          the index of the profiles.
+ *   GET /debug/pprof/profile   a cpu profile, over ?seconds=30 by default.
+ *   GET /debug/pprof/heap      the allocations of live objects.
+ *
+ * With a Server.Logger, every request is logged with its status and
+ * duration once it is answered.
+ *
+ * With a Server.Cache, /solve and /rate answer classic puzzles solved or
+ * rated before from it, and /generate saves the puzzles to it.
+ *
+ * The json api can also run as an AWS Lambda function, see Server.Lambda.
+ *
+ * Everything else is served from the embedded web app in ui/, a page where a
+ * board can be entered or pasted, solved and given hints for.
+ */
+package server
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"log/slog"
+	"math/rand"
+	"mime"
+	"net/http"
+	"net/http/pprof"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/dhedegaard/sudoku.go"
+	"github.com/dhedegaard/sudoku.go/store"
+)
+
+// The largest request body accepted.
+const maxBodySize = 1 << 20
+
+// The web app served at /.
+//
+//go:embed ui
+var ui embed.FS
+
+// Serves the solver over http, the zero value is ready to use.
+type Server struct {
+	// The maximum time spent solving, generating, rating or giving a hint
+	// for a board, 0 for no limit.
+	Timeout time.Duration
+	// The solver used by /solve.
+	Solver sudoku.Solver
+	// The requests per second allowed per client on the api, 0 for no limit.
+	RateLimit float64
+	// The requests a client can make at once before being limited, by
+	// default RateLimit rounded up.
+	RateBurst int
+	// Tells clients with one of the APIKeys apart by their key instead of
+	// by ip address. Keys with their own rate limit are always limited by
+	// key.
+	RateByKey bool
+	// The api keys allowed to use the api, keyed by the key. If not nil,
+	// requests must send one in their Authorization header, ie.
+	// "Authorization: Bearer 3f9a2c".
+	APIKeys map[string]APIKey
+	// The origins of browser apps allowed to call the api, ie.
+	// "https://example.com", or "*" for any.
+	AllowedOrigins []string
+	// Keeps the solutions and ratings of classic puzzles and the puzzles
+	// generated, to answer repeated puzzles from, if not nil: a SQLite
+	// store.Store, or a store.Redis shared by several servers.
+	Cache store.Cache
+	// Serves the profiles of net/http/pprof under /debug/pprof/. They are
+	// not limited like the api, only set it where the server is not
+	// reachable by the public or behind a proxy hiding the path.
+	Profiling bool
+	// Logs every request, and the solver engine of each board at debug
+	// level, if not nil.
+	Logger *slog.Logger
+
+	// Non-zero once WarmUp is done.
+	ready   int32
+	limiter limiter
+	// The puzzles of the days /daily serves, by their seed.
+	dailyLock sync.Mutex
+	daily     map[int64]dailyPuzzle
+	// The handler Lambda serves events with, built on the first one.
+	lambdaOnce    sync.Once
+	lambdaHandler http.Handler
+}
+
+// Solves, generates and rates a puzzle once, so the first requests are not
+// slowed down by setting up, then reports the server as ready on /readyz.
+func (s *Server) WarmUp() {
+	puzzle := sudoku.Generate(sudoku.GenerateOptions{})
+	puzzle.Solve()
+	puzzle.Rate()
+	atomic.StoreInt32(&s.ready, 1)
+}
+
+// Returns the handler serving the endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/solve", s.api(post(s.solve)))
+	mux.Handle("/validate", s.api(post(s.validate)))
+	mux.Handle("/generate", s.api(post(s.generate)))
+	mux.Handle("/rate", s.api(post(s.rate)))
+	mux.Handle("/hint", s.api(post(s.hint)))
+	mux.Handle("/daily", s.api(http.HandlerFunc(s.dailyPuzzle)))
+	mux.Handle("/ws/solve", s.api(s.solveSocket()))
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/readyz", s.readyz)
+	if s.Profiling {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	root, _ := fs.Sub(ui, "ui")
+	mux.Handle("/", http.FileServer(http.FS(root)))
+	if s.Logger != nil {
+		return s.logRequests(mux)
+	}
+	return mux
+}
+
+// Adds CORS headers, and checks the api key and rate limit before the
+// handler.
+func (s *Server) api(handler http.Handler) http.Handler {
+	return s.cors(s.authenticate(s.limit(handler)))
+}
+
+// Only allows POST requests to the handler.
+func post(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, r, http.StatusMethodNotAllowed, errors.New("Method not allowed"))
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// Reads the request body, converting MessagePack to json.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); isMsgpack(mediaType) {
+		return msgpackToJSON(body)
+	}
+	return body, nil
+}
+
+// Reads and parses the board in the request body, writes an error response
+// and returns false if that fails.
+func readBoard(w http.ResponseWriter, r *http.Request) (sudoku.Board, bool) {
+	body, err := readBody(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+	board, err := sudoku.Parse(body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return board, true
+}
+
+// Parses a request body, either a board or a json object with the board and
+// the rules of its variant, see sudoku.Variant.ParsePuzzle.
+func parseVariantBoard(body []byte) (sudoku.Board, sudoku.Variant, error) {
+	return sudoku.Variant{}.ParsePuzzle(body)
+}
+
+// Parses a request body like parseVariantBoard, along with the notes of a
+// game client, see sudoku.ParseNotes.
+func parseGame(body []byte) (sudoku.Puzzle, error) {
+	board, variant, err := parseVariantBoard(body)
+	if err != nil {
+		return sudoku.Puzzle{}, err
+	}
+	notes, err := sudoku.ParseNotes(body, board)
+	if err != nil {
+		return sudoku.Puzzle{}, err
+	}
+	return sudoku.Puzzle{Board: board, Variant: variant, Notes: notes}, nil
+}
+
+// Returns true if the game client sent notes along with the board.
+func hasNotes(game sudoku.Puzzle) bool {
+	return game.PencilMarks != nil || game.Marks != nil
+}
+
+// Writes value as a json response, or as MessagePack if the request accepts
+// it.
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, value interface{}) {
+	if acceptsMsgpack(r) {
+		data, err := encodeMsgpack(value)
+		if err == nil {
+			w.Header().Set("Content-Type", msgpackTypes[0])
+			w.WriteHeader(status)
+			w.Write(data)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// Writes an error as a response, with every problem of the board if it is
+// not valid, ie. {"error":"...","problems":[{"cells":[...],"message":"..."}]}.
+func writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	writeResponse(w, r, status, struct {
+		Error    string           `json:"error"`
+		Problems []sudoku.Problem `json:"problems,omitempty"`
+	}{err.Error(), problems(err)})
+}
+
+// Returns the problems of the board an error is for, or nil if it is not
+// for an invalid board.
+func problems(err error) []sudoku.Problem {
+	var report sudoku.ValidationReport
+	if errors.As(err, &report) {
+		return report.Problems
+	}
+	return nil
+}
+
+// Returns the status code for an error from the sudoku package.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, sudoku.ErrInvalidBoard):
+		return http.StatusBadRequest
+	case errors.Is(err, sudoku.ErrUnsolvable), errors.Is(err, sudoku.ErrNotUnique),
+		errors.Is(err, sudoku.ErrStuck), errors.Is(err, sudoku.ErrSolved),
+		errors.Is(err, sudoku.ErrDifficultyNotReached):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (s *Server) solve(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	game, err := parseGame(body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	solution, err := s.cachedSolve(r.Context(), game.Board, game.Variant)
+	if err != nil {
+		writeError(w, r, errorStatus(err), err)
+		return
+	}
+	if hasNotes(game) {
+		game.Board = solution
+		writeResponse(w, r, http.StatusOK, game)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, solution)
+}
+
+func (s *Server) validate(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	result := struct {
+		Valid    bool             `json:"valid"`
+		Error    string           `json:"error,omitempty"`
+		Problems []sudoku.Problem `json:"problems,omitempty"`
+		sudoku.Notes
+	}{Valid: true}
+	game, err := parseGame(body)
+	if err != nil {
+		result.Valid = false
+		result.Error = err.Error()
+		result.Problems = problems(err)
+	}
+	result.Notes = game.Notes
+	writeResponse(w, r, http.StatusOK, result)
+}
+
+func (s *Server) generate(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// Without a seed, a random puzzle is generated.
+	options := sudoku.GenerateOptions{Seed: rand.Int63()}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &options); err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+	if err := options.Validate(); err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	// Saving the puzzle finds its fingerprint, within the timeout too.
+	ctx, cancel := s.withTimeout(r.Context())
+	defer cancel()
+	puzzle, err := s.generatePuzzle(ctx, options)
+	if err != nil {
+		writeError(w, r, errorStatus(err), err)
+		return
+	}
+	if s.Cache != nil && reflect.DeepEqual(options.Variant, sudoku.Variant{}) {
+		if err := s.Cache.SaveGenerated(ctx, puzzle.Board); err != nil {
+			writeError(w, r, errorStatus(err), err)
+			return
+		}
+	}
+	if options.Variant.AllDots {
+		// The board along with its dots.
+		writeResponse(w, r, http.StatusOK, puzzle)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, puzzle.Board)
+}
+
+func (s *Server) rate(w http.ResponseWriter, r *http.Request) {
+	board, ok := readBoard(w, r)
+	if !ok {
+		return
+	}
+
+	rating, err := s.cachedRate(r.Context(), board)
+	if err != nil {
+		writeError(w, r, errorStatus(err), err)
+		return
+	}
+	writeResponse(w, r, http.StatusOK, rating)
+}
+
+// Returns ctx, cancelled after the server's Timeout if it has one.
+func (s *Server) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.Timeout > 0 {
+		return context.WithTimeout(ctx, s.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Solves a board like solveBoard, answering classic boards from the Cache
+// if it has them and saving their solutions to it, all within the server's
+// timeout.
+func (s *Server) cachedSolve(ctx context.Context, board sudoku.Board, variant sudoku.Variant) (sudoku.Board, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	cached := s.Cache != nil && reflect.DeepEqual(variant, sudoku.Variant{})
+	if cached {
+		solution, found, err := s.Cache.Solution(ctx, board)
+		if found || err != nil {
+			return solution, err
+		}
+	}
+	solution, err := s.solveBoard(ctx, board, variant, nil)
+	if err == nil && cached {
+		err = s.Cache.SaveSolution(ctx, board, solution)
+	}
+	return solution, err
+}
+
+// Rates a board within the server's timeout, answering from the Cache if it
+// has it and saving the rating to it.
+func (s *Server) cachedRate(ctx context.Context, board sudoku.Board) (sudoku.Rating, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	if s.Cache == nil {
+		return board.RateContext(ctx)
+	}
+	rating, found, err := s.Cache.Rating(ctx, board)
+	if found || err != nil {
+		return rating, err
+	}
+	rating, err = board.RateContext(ctx)
+	if err == nil {
+		err = s.Cache.SaveRating(ctx, board, rating)
+	}
+	return rating, err
+}
+
+func (s *Server) hint(w http.ResponseWriter, r *http.Request) {
+	body, err := readBody(w, r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	game, err := parseGame(body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if !reflect.DeepEqual(game.Variant, sudoku.Variant{}) {
+		writeError(w, r, http.StatusBadRequest, errors.New("Only classic sudoku is supported"))
+		return
+	}
+
+	ctx, cancel := s.withTimeout(r.Context())
+	defer cancel()
+	step, err := game.Board.HintContext(ctx)
+	if err != nil {
+		writeError(w, r, errorStatus(err), err)
+		return
+	}
+	if hasNotes(game) {
+		writeResponse(w, r, http.StatusOK, struct {
+			sudoku.Step
+			sudoku.Notes
+		}{step, game.Notes})
+		return
+	}
+	writeResponse(w, r, http.StatusOK, step)
+}
+
+func (s *Server) dailyPuzzle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, r, http.StatusMethodNotAllowed, errors.New("Method not allowed"))
+		return
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	day := today
+	if date := r.URL.Query().Get("date"); date != "" {
+		var err error
+		day, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, fmt.Errorf("Invalid date: %s, expected ie. 2024-06-01", date))
+			return
+		}
+	}
+	var difficulty sudoku.Difficulty
+	if name := r.URL.Query().Get("difficulty"); name != "" {
+		var err error
+		difficulty, err = sudoku.ParseDifficulty(name)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// Every client asks for the same puzzles, generate each once. Only the
+	// days it is today somewhere are kept, so clients asking for every date
+	// cannot fill the memory.
+	options := sudoku.DailyOptions(day, difficulty)
+	puzzle, ok := s.loadDaily(options.Seed)
+	if !ok {
+		generated, err := s.generatePuzzle(r.Context(), options)
+		if err != nil {
+			writeError(w, r, errorStatus(err), err)
+			return
+		}
+		puzzle = generated.Board
+		if !day.Before(today.Add(-dailyWindow)) && !day.After(today.Add(dailyWindow)) {
+			s.storeDaily(options.Seed, dailyPuzzle{day, puzzle}, today)
+		}
+	}
+	writeResponse(w, r, http.StatusOK, puzzle)
+}
+
+// How far from today in UTC the days whose puzzles /daily keeps are.
+const dailyWindow = 24 * time.Hour
+
+// A puzzle of the day, kept while its day is within dailyWindow.
+type dailyPuzzle struct {
+	day   time.Time
+	board sudoku.Board
+}
+
+// Returns the puzzle of the day with the given seed, false if it has not
+// been generated.
+func (s *Server) loadDaily(seed int64) (sudoku.Board, bool) {
+	s.dailyLock.Lock()
+	defer s.dailyLock.Unlock()
+	puzzle, ok := s.daily[seed]
+	return puzzle.board, ok
+}
+
+// Keeps a puzzle of the day, and drops the ones of days no longer within
+// dailyWindow.
+func (s *Server) storeDaily(seed int64, puzzle dailyPuzzle, today time.Time) {
+	s.dailyLock.Lock()
+	defer s.dailyLock.Unlock()
+	if s.daily == nil {
+		s.daily = map[int64]dailyPuzzle{}
+	}
+	for seed, kept := range s.daily {
+		if kept.day.Before(today.Add(-dailyWindow)) {
+			delete(s.daily, seed)
+		}
+	}
+	s.daily[seed] = puzzle
+}
+
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&s.ready) == 0 {
+		writeResponse(w, r, http.StatusServiceUnavailable, map[string]string{"status": "warming up"})
+		return
+	}
+	writeResponse(w, r, http.StatusOK, map[string]string{"status": "ready"})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const easyLine = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+
+// Serves a request to the server's handler and returns the response.
+func serve(s *Server, method string, target string, body string, headers map[string]string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestDaily(t *testing.T) {
+	today := time.Now().UTC().Format("2006-01-02")
+	tests := []struct {
+		target string
+		status int
+		kept   bool
+	}{
+		{"/daily", http.StatusOK, true},
+		{"/daily?date=" + today + "&difficulty=easy", http.StatusOK, true},
+		{"/daily?date=2020-02-29", http.StatusOK, false},
+		{"/daily?date=2999-01-01", http.StatusOK, false},
+		{"/daily?date=2020-02-30", http.StatusBadRequest, false},
+		{"/daily?date=yesterday", http.StatusBadRequest, false},
+		{"/daily?difficulty=impossible", http.StatusBadRequest, false},
+	}
+	for _, test := range tests {
+		t.Run(test.target, func(t *testing.T) {
+			s := &Server{}
+			response := serve(s, "GET", test.target, "", nil)
+			if response.Code != test.status {
+				t.Fatalf("got status %d, want %d: %s", response.Code, test.status, response.Body)
+			}
+			if kept := len(s.daily) > 0; kept != test.kept {
+				t.Errorf("got the puzzle kept: %t, want %t", kept, test.kept)
+			}
+		})
+	}
+
+	// The same day is the same puzzle.
+	s := &Server{}
+	first := serve(s, "GET", "/daily?date=2020-02-29", "", nil).Body.String()
+	if second := serve(s, "GET", "/daily?date=2020-02-29", "", nil).Body.String(); first != second {
+		t.Errorf("got %s and then %s for the same day", first, second)
+	}
+	if other := serve(s, "GET", "/daily?date=2020-03-01", "", nil).Body.String(); first == other {
+		t.Errorf("got %s for two days", first)
+	}
+	if response := serve(s, "POST", "/daily", "", nil); response.Code != http.StatusMethodNotAllowed {
+		t.Errorf("got status %d for a POST, want 405", response.Code)
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	unsolvable := "12345678.........9" + emptyLine[18:]
+	pencilMarks := `[[4,5]` + strings.Repeat(",[]", 80) + `]`
+	solution := "483921657967345821251876493548132976729564138136798245372689514814253769695417382"
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		// Part of the response body.
+		contains string
+	}{
+		{"solve", "POST", "/solve", easyLine, http.StatusOK, "[4,8,3,9,2,1,6,5,7,"},
+		{"solve a variant", "POST", "/solve", `{"board":"` + easyLine + `","diagonals":false}`, http.StatusOK, "[4,8,3,9,2,1,6,5,7,"},
+		{"solve with notes", "POST", "/solve", `{"board":"` + easyLine + `","pencilmarks":` + pencilMarks + `}`, http.StatusOK, `"board":[4,8,3,`},
+		{"solve without a board", "POST", "/solve", "", http.StatusBadRequest, "No input"},
+		{"solve an invalid board", "POST", "/solve", "11" + easyLine[2:], http.StatusBadRequest, `"problems":[`},
+		{"solve an unsolvable board", "POST", "/solve", unsolvable, http.StatusUnprocessableEntity, "no solution"},
+		{"solve with GET", "GET", "/solve", "", http.StatusMethodNotAllowed, "Method not allowed"},
+		{"validate", "POST", "/validate", easyLine, http.StatusOK, `"valid":true`},
+		{"validate an invalid board", "POST", "/validate", "11" + easyLine[2:], http.StatusOK, `"valid":false`},
+		{"generate", "POST", "/generate", `{"seed":1}`, http.StatusOK, "["},
+		{"generate without options", "POST", "/generate", "", http.StatusOK, "["},
+		{"generate bad json", "POST", "/generate", `{"seed":`, http.StatusBadRequest, "error"},
+		{"generate a bad size", "POST", "/generate", `{"size":7}`, http.StatusBadRequest, "Unsupported board size"},
+		{"rate", "POST", "/rate", easyLine, http.StatusOK, `"difficulty"`},
+		{"rate an invalid board", "POST", "/rate", "11" + easyLine[2:], http.StatusBadRequest, "error"},
+		{"hint", "POST", "/hint", easyLine, http.StatusOK, `"technique"`},
+		{"hint a solved board", "POST", "/hint", solution, http.StatusUnprocessableEntity, "already solved"},
+		{"hint a variant", "POST", "/hint", `{"board":"` + easyLine + `","cages":[{"cells":[0,1],"sum":0}]}`, http.StatusBadRequest, "Only classic sudoku"},
+		{"hint an unsolvable board", "POST", "/hint", unsolvable, http.StatusUnprocessableEntity, "no solution"},
+		{"healthz", "GET", "/healthz", "", http.StatusOK, `"ok"`},
+		{"readyz before warming up", "GET", "/readyz", "", http.StatusServiceUnavailable, "warming up"},
+		{"unknown path", "GET", "/unknown", "", http.StatusNotFound, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := serve(&Server{}, test.method, test.target, test.body, nil)
+			if response.Code != test.status {
+				t.Fatalf("got status %d, want %d: %s", response.Code, test.status, response.Body)
+			}
+			if body := response.Body.String(); !strings.Contains(body, test.contains) {
+				t.Errorf("got %s, want it to contain %s", body, test.contains)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := &Server{Timeout: time.Nanosecond}
+	for _, target := range []string{"/solve", "/rate", "/hint"} {
+		if response := serve(s, "POST", target, emptyLine, nil); response.Code != http.StatusServiceUnavailable {
+			t.Errorf("got status %d from %s, want 503: %s", response.Code, target, response.Body)
+		}
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	s := &Server{}
+	s.WarmUp()
+	if response := serve(s, "GET", "/readyz", "", nil); response.Code != http.StatusOK {
+		t.Errorf("got status %d after warming up, want 200", response.Code)
+	}
+}
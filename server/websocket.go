@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The last message sent over the /ws/solve websocket, with the solution or
+// an error. The messages before it are sudoku.Events.
+type wsMessage struct {
+	Type  string       `json:"type"`
+	Board sudoku.Board `json:"board,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// Returns the handler for /ws/solve, which reads a board from the first
+// message and streams the search as it happens.
+func (s *Server) solveSocket() websocket.Handler {
+	return func(ws *websocket.Conn) {
+		defer ws.Close()
+		ws.MaxPayloadBytes = maxBodySize
+
+		var body []byte
+		if err := websocket.Message.Receive(ws, &body); err != nil {
+			return
+		}
+		board, err := sudoku.Parse(body)
+		if err != nil {
+			websocket.JSON.Send(ws, wsMessage{Type: "error", Error: err.Error()})
+			return
+		}
+
+		// The pause after each event, so the search can be followed.
+		var delay time.Duration
+		if ms, err := strconv.Atoi(ws.Request().URL.Query().Get("delay")); err == nil && ms > 0 {
+			delay = time.Duration(ms) * time.Millisecond
+		}
+
+		ctx, cancel := context.WithCancel(ws.Request().Context())
+		defer cancel()
+
+		// Events may come from several workers, and the search is given up
+		// when the client goes away.
+		var mutex sync.Mutex
+		observe := func(event sudoku.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if ctx.Err() != nil {
+				return
+			}
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				cancel()
+				return
+			}
+			if delay > 0 {
+				select {
+				case <-ctx.Done():
+				case <-time.After(delay):
+				}
+			}
+		}
+
+		solution, err := s.solveBoard(ctx, board, sudoku.Variant{}, observe)
+		mutex.Lock()
+		defer mutex.Unlock()
+		if err != nil {
+			websocket.JSON.Send(ws, wsMessage{Type: "error", Error: err.Error()})
+			return
+		}
+		websocket.JSON.Send(ws, wsMessage{Type: "solution", Board: solution})
+	}
+}
@@ -0,0 +1,590 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/bits"
+	"time"
+)
+
+var (
+	// Returned (wrapped) when the board is malformed, see IsValid.
+	ErrInvalidBoard = errors.New("Board is invalid")
+	// Returned when the board is valid, but no solution exists.
+	ErrUnsolvable = errors.New("Board has no solution")
+	// Returned when a unique solution is required, but there are several.
+	ErrNotUnique = errors.New("Board has more than one solution")
+)
+
+// A search algorithm used by a Solver.
+type Engine int
+
+const (
+	// Fills cells with a single candidate, then tries each candidate of the
+	// empty cell with the fewest candidates. Boards larger than 16x16 are
+	// searched with DancingLinks instead, which also finds the values that
+	// fit in a single cell of a unit, unless the variant has rules beyond
+	// its units.
+	Backtracking Engine = iota
+	// Knuth's Algorithm X with dancing links, on the exact cover problem of
+	// the board. Much faster on hard boards and for counting solutions.
+	// Variants with rules beyond each value appearing once in a unit, ie.
+	// killer cages, are searched with backtracking instead.
+	DancingLinks
+)
+
+// Returns the name of the engine, ie. "dlx".
+func (e Engine) String() string {
+	if e == DancingLinks {
+		return "dlx"
+	}
+	return "backtrack"
+}
+
+// Solves boards with a configurable engine, the zero value uses backtracking
+// and the classic rules.
+type Solver struct {
+	Engine Engine
+	// The rules of the sudoku variant being solved.
+	Variant Variant
+	// The number of goroutines Solve uses to explore separate branches of
+	// the search, 0 or 1 solves sequentially. Counting and enumerating
+	// solutions is always sequential.
+	Workers int
+	// Called with every value placed or removed during the search, if set.
+	// With several workers it is called concurrently.
+	Observe func(Event)
+	// Set to the statistics of each search, if not nil. The search of
+	// Solve with several workers counts the work of all of them.
+	Stats *Stats
+	// Called with each value assigned, candidate eliminated and guess
+	// backtracked during the search, if set, ie. to find out why a variant
+	// has no solution. Traced boards are searched with backtracking, by a
+	// single goroutine.
+	Trace func(TraceStep)
+	// Logs the engine searching each board, and why when it is not the one
+	// asked for, at debug level, if not nil.
+	Logger *slog.Logger
+}
+
+// The kind of change in an Event.
+type EventKind int
+
+const (
+	// A value was placed in an empty cell.
+	Place EventKind = iota
+	// A value was removed again, when backtracking.
+	Remove
+)
+
+// Encodes the kind as "place" or "remove", ie. in json.
+func (k EventKind) MarshalText() ([]byte, error) {
+	switch k {
+	case Place:
+		return []byte("place"), nil
+	case Remove:
+		return []byte("remove"), nil
+	}
+	return nil, fmt.Errorf("Unknown event kind: %d", int(k))
+}
+
+// A change to the board during the search.
+type Event struct {
+	Kind  EventKind `json:"type"`
+	Cell  int       `json:"cell"`
+	Value int       `json:"value"`
+}
+
+// The solver used internally, ie. when checking uniqueness while generating.
+var fastSolver = Solver{Engine: DancingLinks}
+
+// Solves the board, returns a solved board, or an error wrapping
+// ErrInvalidBoard or ErrUnsolvable if the board cannot be solved.
+func (b Board) Solve() (Board, error) {
+	return b.SolveContext(context.Background())
+}
+
+// Like Solve, but gives up and returns the context's error as soon as ctx is
+// cancelled or its deadline is exceeded.
+func (b Board) SolveContext(ctx context.Context) (Board, error) {
+	return Solver{}.Solve(ctx, b)
+}
+
+// Counts the solutions of the board, stopping once limit solutions have been
+// found (a limit of 0 or less counts all of them). Returns an error wrapping
+// ErrInvalidBoard if the board is not valid.
+func (b Board) CountSolutions(limit int) (int, error) {
+	return Solver{}.CountSolutions(context.Background(), b, limit)
+}
+
+// Calls fn with each solution of the board as they are found, stopping once
+// limit solutions have been found (a limit of 0 or less finds all of them).
+// Returns an error wrapping ErrInvalidBoard if the board is not valid.
+func (b Board) EachSolution(limit int, fn func(Board)) error {
+	return Solver{}.EachSolution(context.Background(), b, limit, fn)
+}
+
+// Solves the board, see Board.SolveContext.
+func (s Solver) Solve(ctx context.Context, b Board) (Board, error) {
+	if s.Workers > 1 && s.Trace == nil {
+		return s.solveParallel(ctx, b)
+	}
+
+	var result Board
+	err := s.EachSolution(ctx, b, 1, func(solution Board) {
+		result = solution
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ErrUnsolvable
+	}
+	return result, nil
+}
+
+// Counts the solutions of the board, see Board.CountSolutions.
+func (s Solver) CountSolutions(ctx context.Context, b Board, limit int) (int, error) {
+	count := 0
+	err := s.EachSolution(ctx, b, limit, func(Board) {
+		count++
+	})
+	return count, err
+}
+
+// Calls fn with each solution of the board, see Board.EachSolution.
+func (s Solver) EachSolution(ctx context.Context, b Board, limit int, fn func(Board)) error {
+	shape, err := s.Variant.validate(b)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+
+	if s.Stats != nil {
+		*s.Stats = Stats{}
+		defer func(start time.Time) {
+			s.Stats.Elapsed = time.Since(start)
+		}(time.Now())
+	}
+
+	count := 0
+	visit := func(solution Board) bool {
+		fn(b.deepcopy(solution))
+		count++
+		return count == limit
+	}
+	engine, reason := s.Engine, "requested"
+	switch {
+	case engine == Backtracking && len(b) > 256 && shape.constraints == nil:
+		engine, reason = DancingLinks, "board larger than 16x16"
+	case engine == DancingLinks && shape.constraints != nil:
+		engine, reason = Backtracking, "variant has rules beyond its units"
+	}
+	if s.Trace != nil && engine == DancingLinks {
+		engine, reason = Backtracking, "tracing"
+	}
+	if s.Logger != nil {
+		s.Logger.Debug("Selected solver engine", "engine", engine.String(), "reason", reason, "cells", len(b))
+	}
+
+	switch engine {
+	case DancingLinks:
+		search := newDLX(shape, b)
+		search.observe = s.Observe
+		search.stats = s.Stats
+		_, err = search.search(ctx, visit)
+	default:
+		search := newBacktracker(shape, b)
+		search.observe = s.Observe
+		search.stats = s.Stats
+		search.trace = s.Trace
+		for {
+			solution, err := search.next(ctx)
+			if solution == nil || err != nil || visit(solution) {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+func (b Board) deepcopy(board Board) Board {
+	result := make(Board, len(board))
+	copy(result, board)
+	return result
+}
+
+// The board being searched, with the values used in each row, column and
+// box as bitmasks (bit v set for value v).
+type grid struct {
+	*shape
+	cells []int8
+	// Indexed by the unit masked with unitMask, so the index needs no bounds
+	// check.
+	rows, columns, boxes [unitMask + 1]uint32
+	// The values used in the extra units of a variant.
+	extras []uint32
+	// All candidates, bits 1 to the size of the board.
+	allValues uint32
+	// The cells, for the other rules of a variant.
+	values values
+}
+
+// Masks the index of a row, column or box, which is below maxSize.
+const unitMask = 31
+
+// Returns an empty grid of the given shape.
+func emptyGrid(s *shape) grid {
+	g := grid{
+		shape:     s,
+		cells:     make([]int8, s.size*s.size),
+		extras:    make([]uint32, len(s.extraNames)),
+		allValues: (1<<uint(s.size) - 1) << 1,
+	}
+	if s.constraints != nil {
+		g.values = gridValues(g.cells)
+	}
+	return g
+}
+
+// Returns the grid of a board of the given shape, which must be valid.
+func newGrid(s *shape, b Board) grid {
+	g := emptyGrid(s)
+	for pos, val := range b {
+		if val != 0 {
+			g.set(pos, val)
+		}
+	}
+	return g
+}
+
+// Places val at pos, which must be empty.
+func (g *grid) set(pos int, val int) {
+	bit := uint32(1) << uint(val)
+	unit := &g.units[pos]
+	g.cells[pos] = int8(val)
+	g.rows[unit[0]&unitMask] |= bit
+	g.columns[unit[1]&unitMask] |= bit
+	g.boxes[unit[2]&unitMask] |= bit
+	for _, extra := range g.extraUnits[pos] {
+		g.extras[extra] |= bit
+	}
+}
+
+// Returns the values that can be placed at pos as a bitmask.
+func (g *grid) candidates(pos int) uint32 {
+	unit := &g.units[pos]
+	used := g.rows[unit[0]&unitMask] | g.columns[unit[1]&unitMask] | g.boxes[unit[2]&unitMask]
+	for _, extra := range g.extraUnits[pos] {
+		used |= g.extras[extra]
+	}
+	return g.allValues &^ used
+}
+
+// Returns the candidates of pos that the other rules of the variant allow,
+// kept out of candidates so it stays cheap to inline.
+func (g *grid) allowed(pos int, candidates uint32) uint32 {
+	for _, c := range g.cellConstraints[pos] {
+		candidates &= g.constraints[c].allowed(g.values, pos)
+	}
+	return candidates
+}
+
+// Returns the grid as a board.
+func (g *grid) board() Board {
+	board := make(Board, len(g.cells))
+	for pos, val := range g.cells {
+		board[pos] = int(val)
+	}
+	return board
+}
+
+// Clears pos, which must have a value.
+func (g *grid) unset(pos int) {
+	bit := uint32(1) << uint(g.cells[pos])
+	unit := &g.units[pos]
+	g.cells[pos] = 0
+	g.rows[unit[0]&unitMask] &^= bit
+	g.columns[unit[1]&unitMask] &^= bit
+	g.boxes[unit[2]&unitMask] &^= bit
+	for _, extra := range g.extraUnits[pos] {
+		g.extras[extra] &^= bit
+	}
+}
+
+// A backtracking search on a single grid, where values are assigned and
+// undone in place instead of copying the grid for each branch. The search
+// keeps its own stack instead of recursing, so it can be paused after each
+// solution and resumed.
+type backtracker struct {
+	grid
+	// The cells assigned by propagation, in order, so they can be undone.
+	trail  []int16
+	placed int
+	// The branches being explored, innermost last, made with the first
+	// branch.
+	stack []branch
+	// True when a value was just tried and the new node must be propagated.
+	enter bool
+	// The trail length to undo to when resuming after a solution, or -1.
+	leaf int
+	// True when every branch has been explored.
+	done bool
+	// The board returned for each solution, made for the first one and
+	// reused.
+	board Board
+	// Called with each change to the grid, if set.
+	observe func(Event)
+	// Counts the work done, if set.
+	stats *Stats
+	// Called with each step of the search, if set.
+	trace func(TraceStep)
+}
+
+// A node in the search, branching on the candidates of a cell.
+type branch struct {
+	// The trail length before the node was propagated.
+	mark int
+	// The cell being branched on and the candidates not tried yet.
+	pos        int
+	candidates uint32
+}
+
+func newBacktracker(s *shape, b Board) *backtracker {
+	return &backtracker{
+		grid:  newGrid(s, b),
+		trail: make([]int16, len(b)),
+		enter: true,
+		leaf:  -1,
+	}
+}
+
+// Places val at pos, reporting it to the observer.
+func (s *backtracker) place(pos int, val int) {
+	s.set(pos, val)
+	if s.observe != nil {
+		s.observe(Event{Place, pos, val})
+	}
+}
+
+// Clears pos, reporting it to the observer.
+func (s *backtracker) remove(pos int) {
+	if s.observe != nil {
+		s.observe(Event{Remove, pos, int(s.cells[pos])})
+	}
+	s.unset(pos)
+}
+
+// Reports a step at pos to the tracer, which must be set.
+func (s *backtracker) traceStep(kind TraceKind, pos int, val int, reason string) {
+	s.trace(TraceStep{kind, Coordinate{pos / s.size, pos % s.size}, val, len(s.stack), reason})
+}
+
+// Reports the candidates of pos ruled out by each rule of the variant on it,
+// the first rule ruling out a value reporting it.
+func (s *backtracker) traceEliminations(pos int, candidates uint32) {
+	for _, c := range s.cellConstraints[pos] {
+		rule := s.constraints[c]
+		allowed := rule.allowed(s.values, pos)
+		for removed := candidates &^ allowed; removed != 0; removed &= removed - 1 {
+			s.traceStep(TraceEliminate, pos, bits.TrailingZeros32(removed), rule.rule())
+		}
+		candidates &= allowed
+	}
+}
+
+// Places val at pos and records it on the trail.
+func (s *backtracker) assign(pos int, val int) {
+	s.place(pos, val)
+	s.trail[s.placed] = int16(pos)
+	s.placed++
+}
+
+// Undoes the assignments made since the trail had mark cells.
+func (s *backtracker) undo(mark int) {
+	for s.placed > mark {
+		s.placed--
+		s.remove(int(s.trail[s.placed]))
+	}
+}
+
+// Fills every cell with a single candidate, until there are none left.
+// Returns the empty cell with the fewest candidates and its candidates, -1
+// if the board is full, or false if a cell has no candidates.
+func (s *backtracker) propagate() (int, uint32, bool) {
+	var best int
+	var bestCandidates uint32
+	// The grid kept in locals, and the rules of a variant kept out of the
+	// loop, so the scan of a classic board stays in registers.
+	cells, units := s.cells, s.units[:len(s.cells)]
+	rows, columns, boxes := &s.rows, &s.columns, &s.boxes
+	all := s.allValues
+	variant := len(s.extras) > 0 || s.constraints != nil
+	for progress := true; progress; {
+		progress = false
+		if s.stats != nil {
+			s.stats.Propagations++
+		}
+		best = -1
+		bestCount := s.size + 1
+		for pos := range cells {
+			if cells[pos] != 0 {
+				continue
+			}
+			unit := &units[pos]
+			candidates := all &^ (rows[unit[0]&unitMask] | columns[unit[1]&unitMask] | boxes[unit[2]&unitMask])
+			if variant {
+				candidates = s.variantCandidates(pos, candidates)
+			}
+			count := bits.OnesCount32(candidates)
+			if count > 1 {
+				if count < bestCount {
+					best = pos
+					bestCount = count
+					bestCandidates = candidates
+				}
+				continue
+			}
+			if count == 0 {
+				if s.trace != nil {
+					s.traceStep(TraceDeadEnd, pos, 0, "")
+				}
+				return -1, 0, false
+			}
+			s.single(pos, bits.TrailingZeros32(candidates))
+			progress = true
+		}
+	}
+	return best, bestCandidates, true
+}
+
+// Returns the candidates of pos the extra units and other rules of the
+// variant leave, tracing the eliminations that leave fewer than two.
+func (s *backtracker) variantCandidates(pos int, candidates uint32) uint32 {
+	for _, extra := range s.extraUnits[pos] {
+		candidates &^= s.extras[extra]
+	}
+	if s.constraints == nil {
+		return candidates
+	}
+	allowed := s.allowed(pos, candidates)
+	if s.trace != nil && allowed != candidates && bits.OnesCount32(allowed) < 2 {
+		s.traceEliminations(pos, candidates)
+	}
+	return allowed
+}
+
+// Assigns val to pos, its single candidate.
+func (s *backtracker) single(pos int, val int) {
+	s.assign(pos, val)
+	if s.trace != nil {
+		s.traceStep(TraceAssign, pos, val, "single")
+	}
+}
+
+// Searches for the next solution, returns nil when there are no more. The
+// returned board is only valid until the next call.
+func (s *backtracker) next(ctx context.Context) (Board, error) {
+	// Resume after the previous solution.
+	if s.leaf >= 0 {
+		s.undo(s.leaf)
+		s.leaf = -1
+	}
+
+	for !s.done {
+		// Stop searching when the caller is no longer interested.
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if s.enter {
+			s.enter = false
+			if s.stats != nil {
+				s.stats.enter(len(s.stack))
+			}
+			mark := s.placed
+			best, candidates, ok := s.propagate()
+			switch {
+			case !ok:
+				// Dead end.
+				s.undo(mark)
+			case best < 0:
+				// Every cell has a value.
+				s.leaf = mark
+				if s.board == nil {
+					s.board = make(Board, len(s.cells))
+				}
+				for pos, val := range s.cells {
+					s.board[pos] = int(val)
+				}
+				return s.board, nil
+			default:
+				if s.stack == nil {
+					s.stack = make([]branch, 0, len(s.cells))
+				}
+				s.stack = append(s.stack, branch{mark, best, candidates})
+				continue
+			}
+		}
+
+		// Try the next candidate of the innermost branch, or give up on it.
+		if len(s.stack) == 0 {
+			s.done = true
+			break
+		}
+		top := &s.stack[len(s.stack)-1]
+		if s.cells[top.pos] != 0 {
+			if s.trace != nil {
+				s.traceStep(TraceBacktrack, top.pos, int(s.cells[top.pos]), "")
+			}
+			s.remove(top.pos)
+			if s.stats != nil {
+				s.stats.Backtracks++
+			}
+		}
+		if top.candidates == 0 {
+			s.undo(top.mark)
+			s.stack = s.stack[:len(s.stack)-1]
+			continue
+		}
+		val := bits.TrailingZeros32(top.candidates)
+		s.place(top.pos, val)
+		if s.trace != nil {
+			s.traceStep(TraceAssign, top.pos, val, "guess")
+		}
+		top.candidates &= top.candidates - 1
+		s.enter = true
+	}
+
+	// No (more) solutions found.
+	return nil, nil
+}
+
+// Iterates over the solutions of a board one at a time, using backtracking.
+// The search is paused between calls to Next.
+type Solutions struct {
+	search *backtracker
+}
+
+// Returns an iterator over the solutions of the board, or an error wrapping
+// ErrInvalidBoard if the board is not valid.
+func (b Board) Solutions() (*Solutions, error) {
+	_, err := b.IsValid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	s, _ := b.shape()
+	return &Solutions{newBacktracker(s, b)}, nil
+}
+
+// Resumes the search and returns the next solution, or nil when there are
+// no more. Returns the context's error if ctx is done before a solution is
+// found, the search can be resumed afterwards.
+func (it *Solutions) Next(ctx context.Context) (Board, error) {
+	board, err := it.search.next(ctx)
+	if board == nil || err != nil {
+		return nil, err
+	}
+	return board.deepcopy(board), nil
+}
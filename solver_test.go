@@ -0,0 +1,62 @@
+package sudoku
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// Solves the board in line b.N times.
+func benchmarkSolve(b *testing.B, line string) {
+	benchmarkSolveVariant(b, Variant{}, line)
+}
+
+// Solves the board in line under the variant's rules b.N times.
+func benchmarkSolveVariant(b *testing.B, variant Variant, line string) {
+	board, err := ParseLine(line)
+	if err != nil {
+		b.Fatal(err)
+	}
+	solver := Solver{Variant: variant}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := solver.Solve(context.Background(), board); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSolveEasy(b *testing.B) {
+	benchmarkSolve(b, "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..")
+}
+
+// The first puzzle of the top95 collection.
+func BenchmarkSolveTop95(b *testing.B) {
+	benchmarkSolve(b, "4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......")
+}
+
+func BenchmarkSolveHard(b *testing.B) {
+	benchmarkSolve(b, "1.......2.9.4...5...6...7...5.9.3.......7.......85..4.7.....6...3...9.8...2.....1")
+}
+
+// The search has nothing to propagate, and must guess its way down the
+// first branches.
+func BenchmarkSolveEmpty(b *testing.B) {
+	benchmarkSolve(b, strings.Repeat(".", 81))
+}
+
+// A puzzle with an empty top row, whose solution starts with 987654321 so
+// the search tries the wrong values there first.
+func BenchmarkSolveEmptyTop(b *testing.B) {
+	benchmarkSolve(b, "..............3.85..1.2.......5.7.....4...1...9.......5......73..2.1........4...9")
+}
+
+// A sandwich sudoku whose only given values are the 5s, most of the search
+// is placing the 1s and 9s.
+func BenchmarkSolveSandwich(b *testing.B) {
+	variant := Variant{
+		SandwichRows:    []int{0, 35, 23, 0, 0, 0, 0, 18, 0},
+		SandwichColumns: []int{10, 16, 25, 4, 16, 25, 11, 13, 0},
+	}
+	benchmarkSolveVariant(b, variant, "........5.5............5.....5.........5...........5......5....5...............5.")
+}
@@ -0,0 +1,31 @@
+package sudoku
+
+import (
+	"strings"
+)
+
+// The words of the numbers up to the largest size.
+var numberWords = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen",
+	"nineteen", "twenty", "twenty-one", "twenty-two", "twenty-three", "twenty-four", "twenty-five",
+	"twenty-six", "twenty-seven", "twenty-eight", "twenty-nine", "thirty", "thirty-one"}
+
+// Returns the board described in plain sentences, a line per row, ie.
+// "Row one: five, blank, blank, seven, ...", for screen readers and voice
+// assistants.
+func (b Board) Spoken() string {
+	size := boardSize(len(b))
+	lines := make([]string, 0, size)
+	for y := 0; y < size; y++ {
+		words := make([]string, 0, size)
+		for _, val := range b[y*size : (y+1)*size] {
+			if val == 0 {
+				words = append(words, "blank")
+			} else {
+				words = append(words, numberWords[val])
+			}
+		}
+		lines = append(lines, "Row "+numberWords[y+1]+": "+strings.Join(words, ", ")+".")
+	}
+	return strings.Join(lines, "\n")
+}
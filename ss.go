@@ -0,0 +1,63 @@
+package sudoku
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Parses a board in the Simple Sudoku .ss format, ie:
+//
+//	*-----------*
+//	|..3|.2.|6..|
+//	|9..|3.5|..1|
+//	|..1|8.6|4..|
+//	|---+---+---|
+//	...
+//	*-----------*
+//
+// The '|', '-', '+' and '*' separators and the borders are optional, so the
+// pretty String format is read as well. Returns an error if the board is not
+// valid (wrapping ErrInvalidBoard).
+func ParseSS(data []byte) (Board, error) {
+	board, err := parseSS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = board.IsValid()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	return board, nil
+}
+
+func parseSS(data []byte) (Board, error) {
+	line := make([]byte, 0, 81)
+	for _, c := range data {
+		switch c {
+		case '|', '-', '+', '*', ' ', '\t', '\r', '\n':
+		default:
+			line = append(line, c)
+		}
+	}
+	if len(line) != 81 {
+		error := fmt.Sprintf("Expected 81 cells, got %d", len(line))
+		return nil, errors.New(error)
+	}
+	return parseLine(string(line))
+}
+
+// Returns the board in the Simple Sudoku .ss format, with borders.
+func (b Board) SS() string {
+	buffer := bytes.NewBufferString("*-----------*\n")
+	line := b.Line()
+	for y := 0; y < 9; y++ {
+		if y > 0 && y%3 == 0 {
+			buffer.WriteString("|---+---+---|\n")
+		}
+		fmt.Fprintf(buffer, "|%s|%s|%s|\n", line[y*9:y*9+3], line[y*9+3:y*9+6], line[y*9+6:y*9+9])
+	}
+	buffer.WriteString("*-----------*")
+	return buffer.String()
+}
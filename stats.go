@@ -0,0 +1,40 @@
+package sudoku
+
+import (
+	"time"
+)
+
+// Counts of the work done by a search, ie. to compare engines or spot
+// boards that are unusually hard to solve.
+type Stats struct {
+	// The nodes of the search tree visited, the root included.
+	Nodes int `json:"nodes"`
+	// The values tried and removed again, after they led to a dead end or
+	// to a solution when looking for more.
+	Backtracks int `json:"backtracks"`
+	// The most values tried at once, nested in each other.
+	MaxDepth int `json:"max_depth"`
+	// With backtracking, the passes over the board filling cells with a
+	// single candidate. With dancing links, the constraints covered.
+	Propagations int `json:"propagations"`
+	// The time spent searching.
+	Elapsed time.Duration `json:"elapsed_ns"`
+}
+
+// Adds the counts of other to the stats, keeping the largest depth.
+func (s *Stats) add(other Stats) {
+	s.Nodes += other.Nodes
+	s.Backtracks += other.Backtracks
+	s.Propagations += other.Propagations
+	if other.MaxDepth > s.MaxDepth {
+		s.MaxDepth = other.MaxDepth
+	}
+}
+
+// Records a node at depth.
+func (s *Stats) enter(depth int) {
+	s.Nodes++
+	if depth > s.MaxDepth {
+		s.MaxDepth = depth
+	}
+}
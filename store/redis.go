@@ -0,0 +1,131 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The prefix of the Redis keys, followed by the kind of value and the
+// fingerprint, ie. "sudoku:solution:3f9a...".
+const redisPrefix = "sudoku:"
+
+// Puzzles kept in Redis, shared by every server using it: the canonical
+// solution and rating of each puzzle, and each canonical puzzle generated,
+// as strings. Safe for concurrent use.
+type Redis struct {
+	// How long a key is kept after it is last saved, 0 to keep it until
+	// Redis evicts it.
+	Expiry time.Duration
+
+	client *redis.Client
+}
+
+// Connects to the Redis server at url, ie. "redis://localhost:6379/0".
+func OpenRedis(url string) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(options)
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &Redis{client: client}, nil
+}
+
+// Closes the connections to the server.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
+
+// Returns the value of the key of kind for the puzzle, or false if it is not
+// set.
+func (r *Redis) get(ctx context.Context, kind string, k key) (string, bool, error) {
+	value, err := r.client.Get(ctx, redisPrefix+kind+":"+k.fingerprint).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// Sets the key of kind for the puzzle to value, expiring after Expiry.
+func (r *Redis) set(ctx context.Context, kind string, k key, value interface{}) error {
+	return r.client.Set(ctx, redisPrefix+kind+":"+k.fingerprint, value, r.Expiry).Err()
+}
+
+// Returns the solution of the puzzle, or false if it has not been saved.
+func (r *Redis) Solution(ctx context.Context, puzzle sudoku.Board) (sudoku.Board, bool, error) {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	line, found, err := r.get(ctx, "solution", k)
+	if !found || err != nil {
+		return nil, false, err
+	}
+	solution, err := k.fromCanonical(line)
+	if err != nil {
+		return nil, false, err
+	}
+	return solution, true, nil
+}
+
+// Saves the solution of the puzzle.
+func (r *Redis) SaveSolution(ctx context.Context, puzzle sudoku.Board, solution sudoku.Board) error {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return err
+	}
+	canonical, err := k.transform.Apply(solution)
+	if err != nil {
+		return err
+	}
+	return r.set(ctx, "solution", k, canonical.Line())
+}
+
+// Returns the rating of the puzzle, or false if it has not been saved.
+func (r *Redis) Rating(ctx context.Context, puzzle sudoku.Board) (sudoku.Rating, bool, error) {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return sudoku.Rating{}, false, err
+	}
+	data, found, err := r.get(ctx, "rating", k)
+	if !found || err != nil {
+		return sudoku.Rating{}, false, err
+	}
+	rating := sudoku.Rating{}
+	if err := json.Unmarshal([]byte(data), &rating); err != nil {
+		return sudoku.Rating{}, false, err
+	}
+	return rating, true, nil
+}
+
+// Saves the rating of the puzzle.
+func (r *Redis) SaveRating(ctx context.Context, puzzle sudoku.Board, rating sudoku.Rating) error {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return err
+	}
+	data, err := json.Marshal(rating)
+	if err != nil {
+		return err
+	}
+	return r.set(ctx, "rating", k, data)
+}
+
+// Saves a generated puzzle.
+func (r *Redis) SaveGenerated(ctx context.Context, puzzle sudoku.Board) error {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return err
+	}
+	return r.set(ctx, "generated", k, k.canonical.Line())
+}
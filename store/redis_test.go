@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// Needs a Redis server to write to, ie.
+// SUDOKU_TEST_REDIS=redis://localhost:6379/15 go test ./store.
+func TestRedis(t *testing.T) {
+	url := os.Getenv("SUDOKU_TEST_REDIS")
+	if url == "" {
+		t.Skip("SUDOKU_TEST_REDIS is not set")
+	}
+	r, err := OpenRedis(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.Expiry = time.Minute
+	ctx := context.Background()
+
+	puzzle, err := sudoku.ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := puzzle.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SaveSolution(ctx, puzzle, solution); err != nil {
+		t.Fatal(err)
+	}
+	got, found, err := r.Solution(ctx, puzzle)
+	if !found || err != nil || got.Line() != solution.Line() {
+		t.Errorf("got %s, %t, %v, want %s", got.Line(), found, err, solution.Line())
+	}
+	if err := r.SaveGenerated(ctx, puzzle); err != nil {
+		t.Fatal(err)
+	}
+	k, _, err := keyOf(ctx, puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kind := range []string{"solution", "generated"} {
+		ttl, err := r.client.TTL(ctx, redisPrefix+kind+":"+k.fingerprint).Result()
+		if err != nil || ttl <= 0 || ttl > time.Minute {
+			t.Errorf("got expiry %v, %v for the %s, want up to a minute", ttl, err, kind)
+		}
+	}
+}
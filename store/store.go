@@ -0,0 +1,214 @@
+/* Package store keeps puzzles, their solutions and ratings in a SQLite
+ * database, or in Redis to share them between servers, looked up by the
+ * fingerprint of the puzzle (see sudoku.Board.Fingerprint), so a puzzle
+ * solved or rated once, or any puzzle it can be transformed to, is answered
+ * from the database from then on.
+ *
+ * Puzzles are kept in their canonical form, along with the solution and
+ * rating of it, and solutions are transformed back to the puzzle looked up.
+ * Only classic sudoku up to 9x9 is kept, as the canonical form of larger
+ * boards is slow to find: the other boards are never found, and saving them
+ * does nothing. A Store can be limited to its newest puzzles with
+ * MaxPuzzles.
+ */
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+// The tables, created when missing. A puzzle has a row once it has been
+// solved, rated or generated.
+const schema = `
+CREATE TABLE IF NOT EXISTS puzzles (
+	fingerprint TEXT PRIMARY KEY,
+	puzzle TEXT NOT NULL,
+	solution TEXT,
+	rating TEXT,
+	generated INTEGER NOT NULL DEFAULT 0
+)`
+
+// Keeps the solutions and ratings of puzzles, and the puzzles generated:
+// a Store or Redis.
+type Cache interface {
+	// Returns the solution of the puzzle, or false if it has not been saved.
+	// Finding the puzzle's fingerprint gives up with the context's error
+	// once ctx is done, like the lookup.
+	Solution(ctx context.Context, puzzle sudoku.Board) (sudoku.Board, bool, error)
+	SaveSolution(ctx context.Context, puzzle sudoku.Board, solution sudoku.Board) error
+	// Returns the rating of the puzzle, or false if it has not been saved.
+	Rating(ctx context.Context, puzzle sudoku.Board) (sudoku.Rating, bool, error)
+	SaveRating(ctx context.Context, puzzle sudoku.Board, rating sudoku.Rating) error
+	SaveGenerated(ctx context.Context, puzzle sudoku.Board) error
+}
+
+// A SQLite database of puzzles, safe for concurrent use.
+type Store struct {
+	// The most puzzles kept, 0 for no limit: saving a new puzzle beyond it
+	// deletes the puzzles saved first. Set it before using the store.
+	MaxPuzzles int
+
+	db *sql.DB
+}
+
+// Opens the database at path, creating it if it does not exist.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// A puzzle in canonical form, with its fingerprint and the transform making
+// it from the puzzle.
+type key struct {
+	fingerprint string
+	canonical   sudoku.Board
+	transform   sudoku.Transform
+}
+
+// Returns the key of a puzzle, or false if it is not kept, ie. it is not
+// valid and the solver reports why.
+func keyOf(ctx context.Context, puzzle sudoku.Board) (key, bool, error) {
+	if len(puzzle) > 81 {
+		return key{}, false, nil
+	}
+	if _, err := puzzle.IsValid(); err != nil {
+		return key{}, false, nil
+	}
+	transform, err := puzzle.CanonicalTransformContext(ctx)
+	if err != nil {
+		return key{}, false, err
+	}
+	canonical, err := transform.Apply(puzzle)
+	if err != nil {
+		return key{}, false, err
+	}
+	// The same as canonical.Fingerprint(), without canonicalizing again.
+	sum := sha256.Sum256([]byte(canonical.Line()))
+	return key{hex.EncodeToString(sum[:]), canonical, transform}, true, nil
+}
+
+// Returns the board of the puzzle from the line format of its canonical
+// form, ie. its solution.
+func (k key) fromCanonical(line string) (sudoku.Board, error) {
+	canonical, err := sudoku.Parse([]byte(line))
+	if err != nil {
+		return nil, err
+	}
+	return k.transform.Inverse().Apply(canonical)
+}
+
+// Returns the solution of the puzzle, or false if it has not been saved.
+func (s *Store) Solution(ctx context.Context, puzzle sudoku.Board) (sudoku.Board, bool, error) {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	var line sql.NullString
+	err = s.db.QueryRowContext(ctx, "SELECT solution FROM puzzles WHERE fingerprint = ?", k.fingerprint).Scan(&line)
+	if err == sql.ErrNoRows || err == nil && !line.Valid {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	solution, err := k.fromCanonical(line.String)
+	if err != nil {
+		return nil, false, err
+	}
+	return solution, true, nil
+}
+
+// Saves the solution of the puzzle.
+func (s *Store) SaveSolution(ctx context.Context, puzzle sudoku.Board, solution sudoku.Board) error {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return err
+	}
+	canonical, err := k.transform.Apply(solution)
+	if err != nil {
+		return err
+	}
+	return s.save(ctx, `INSERT INTO puzzles (fingerprint, puzzle, solution) VALUES (?, ?, ?)
+		ON CONFLICT (fingerprint) DO UPDATE SET solution = excluded.solution`,
+		k.fingerprint, k.canonical.Line(), canonical.Line())
+}
+
+// Returns the rating of the puzzle, or false if it has not been saved.
+func (s *Store) Rating(ctx context.Context, puzzle sudoku.Board) (sudoku.Rating, bool, error) {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return sudoku.Rating{}, false, err
+	}
+	var data sql.NullString
+	err = s.db.QueryRowContext(ctx, "SELECT rating FROM puzzles WHERE fingerprint = ?", k.fingerprint).Scan(&data)
+	if err == sql.ErrNoRows || err == nil && !data.Valid {
+		return sudoku.Rating{}, false, nil
+	} else if err != nil {
+		return sudoku.Rating{}, false, err
+	}
+	rating := sudoku.Rating{}
+	if err := json.Unmarshal([]byte(data.String), &rating); err != nil {
+		return sudoku.Rating{}, false, err
+	}
+	return rating, true, nil
+}
+
+// Saves the rating of the puzzle.
+func (s *Store) SaveRating(ctx context.Context, puzzle sudoku.Board, rating sudoku.Rating) error {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return err
+	}
+	data, err := json.Marshal(rating)
+	if err != nil {
+		return err
+	}
+	return s.save(ctx, `INSERT INTO puzzles (fingerprint, puzzle, rating) VALUES (?, ?, ?)
+		ON CONFLICT (fingerprint) DO UPDATE SET rating = excluded.rating`,
+		k.fingerprint, k.canonical.Line(), string(data))
+}
+
+// Saves a generated puzzle.
+func (s *Store) SaveGenerated(ctx context.Context, puzzle sudoku.Board) error {
+	k, ok, err := keyOf(ctx, puzzle)
+	if !ok || err != nil {
+		return err
+	}
+	return s.save(ctx, `INSERT INTO puzzles (fingerprint, puzzle, generated) VALUES (?, ?, 1)
+		ON CONFLICT (fingerprint) DO UPDATE SET generated = 1`,
+		k.fingerprint, k.canonical.Line())
+}
+
+// Saves a puzzle with query, then deletes the puzzles saved first if there
+// are more than MaxPuzzles. New rows get the largest rowid yet, and rows are
+// only deleted from the smallest ones, so the newest puzzles are those with
+// the last MaxPuzzles rowids.
+func (s *Store) save(ctx context.Context, query string, args ...interface{}) error {
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	if s.MaxPuzzles <= 0 {
+		return nil
+	}
+	_, err := s.db.ExecContext(ctx, "DELETE FROM puzzles WHERE rowid <= (SELECT MAX(rowid) FROM puzzles) - ?", s.MaxPuzzles)
+	return err
+}
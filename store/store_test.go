@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dhedegaard/sudoku.go"
+)
+
+const easyLine = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+
+// Opens a new database in a temporary directory.
+func openTest(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "puzzles.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStoreSolution(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	puzzle, err := sudoku.ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := puzzle.Solve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := s.Solution(ctx, puzzle); found || err != nil {
+		t.Fatalf("got %t, %v before saving, want false, nil", found, err)
+	}
+	if err := s.SaveSolution(ctx, puzzle, solution); err != nil {
+		t.Fatal(err)
+	}
+
+	// Equivalent puzzles are answered with their own solution.
+	transform := sudoku.Identity(9).Rotate().SwapBands(3, 0, 2).Relabel([]int{9, 8, 7, 6, 5, 4, 3, 2, 1})
+	for name, board := range map[string]sudoku.Board{"same": puzzle, "transformed": nil} {
+		if board == nil {
+			board, _ = transform.Apply(puzzle)
+		}
+		want, err := board.Solve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, found, err := s.Solution(ctx, board)
+		if !found || err != nil || got.Line() != want.Line() {
+			t.Errorf("%s: got %s, %t, %v, want %s", name, got.Line(), found, err, want.Line())
+		}
+	}
+
+	// Boards that are not kept are never found.
+	large := make(sudoku.Board, 256)
+	if err := s.SaveSolution(ctx, large, large); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, err := s.Solution(ctx, large); found || err != nil {
+		t.Errorf("got %t, %v for a 16x16 board, want false, nil", found, err)
+	}
+}
+
+func TestStoreRating(t *testing.T) {
+	s := openTest(t)
+	ctx := context.Background()
+	puzzle, err := sudoku.ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err := puzzle.Rate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveRating(ctx, puzzle, rating); err != nil {
+		t.Fatal(err)
+	}
+	got, found, err := s.Rating(ctx, puzzle)
+	if !found || err != nil || got.Score != rating.Score || got.Difficulty != rating.Difficulty {
+		t.Errorf("got %+v, %t, %v, want %+v", got, found, err, rating)
+	}
+}
+
+func TestStoreMaxPuzzles(t *testing.T) {
+	s := openTest(t)
+	s.MaxPuzzles = 3
+	ctx := context.Background()
+	puzzles := []sudoku.Board{}
+	for seed := int64(1); seed <= 5; seed++ {
+		puzzle := sudoku.Generate(sudoku.GenerateOptions{Seed: seed})
+		if err := s.SaveGenerated(ctx, puzzle); err != nil {
+			t.Fatal(err)
+		}
+		puzzles = append(puzzles, puzzle)
+	}
+	// Saving a puzzle again keeps it where it was.
+	if err := s.SaveGenerated(ctx, puzzles[2]); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM puzzles").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("got %d puzzles, want 3", count)
+	}
+	for i, puzzle := range puzzles {
+		k, _, err := keyOf(ctx, puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		if err := s.db.QueryRow("SELECT COUNT(*) FROM puzzles WHERE fingerprint = ?", k.fingerprint).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if kept := i >= 2; (n == 1) != kept {
+			t.Errorf("puzzle %d: got %d rows, want it kept: %t", i, n, kept)
+		}
+	}
+}
+
+func TestStoreCancelled(t *testing.T) {
+	s := openTest(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	puzzle, err := sudoku.ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Solution(ctx, puzzle); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+}
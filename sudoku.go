@@ -1,192 +1,440 @@
-/* This application takes a json sudoku board as input (stdin), and returns a
- * sudoku board in json as output (stdout).
- * If an error occurs (ie board invalid, input not valid) an error string is
- * written to stderr and no stdout is supplied.
+/* Package sudoku parses, validates and solves sudoku boards.
+ *
+ * A board is represented as a flat slice of 81 integers, row by row, where 0
+ * denotes an empty cell. Other sizes are supported up to 31x31, ie. 4x4
+ * boards with 2x2 boxes, 6x6 boards with 2x3 boxes (2 rows of 3 cells), 12x12
+ * boards with 3x4 boxes, 16x16 and 25x25 boards, as size*size integers with
+ * the values 1 to the size of the board, written with the digits 1-9 and A-Z
+ * in the line format. See Variant.BoxRows for other box dimensions. The same
+ * representation is used for the JSON encoding, so a board can be passed
+ * directly to encoding/json.
+ *
+ * Variants of sudoku with other rules, ie. jigsaw sudoku, are solved by a
+ * Solver with the rules in its Variant.
  */
-package main
+package sudoku
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"os"
+	"strings"
+	"sync"
 )
 
 type Board []int
 
-// Returns true/false, and an error if the board is not valid.
-func (b Board) IsValid() (bool, error) {
-	// Validate the length of the board.
-	if len(b) != 81 {
-		return false, errors.New("Board is not 9x9.")
+// The dimensions of a board: its size, the number of cells in each row,
+// column and box and the largest value, and the rows and columns of a box.
+type shape struct {
+	size, boxRows, boxColumns int
+	// What the boxes are called in errors, "region" if they are irregular.
+	boxName string
+	// The row, column and box of each cell.
+	units [][3]int
+	// The cells of each unit: the rows, then the columns, then the boxes,
+	// then the extra units of a variant.
+	unitCells [][]int
+	// The names of the extra units, ie. "diagonal 1", and the extra units
+	// containing each cell, as indexes from the first extra unit.
+	extraNames []string
+	extraUnits [][]int
+	// The other rules of a variant, and the ones applying to each cell, as
+	// indexes in constraints.
+	constraints     []constraint
+	cellConstraints [][]int
+	// The cells sharing a unit with each cell.
+	peers [][]int
+}
+
+// The largest number of rows of a board, values are kept as the bits of a
+// uint32.
+const maxSize = 31
+
+var (
+	shapesLock sync.RWMutex
+	// The default shapes of the boards used so far, by their number of
+	// cells.
+	shapes = map[int]*shape{}
+)
+
+// Returned (wrapped) for boards with an unsupported number of cells.
+var ErrBadSize = errors.New("Board is not a supported size, ie. 4x4, 6x6, 9x9, 12x12, 16x16 or 25x25")
+
+// Returned (wrapped) for a board with a value out of range, at position Pos
+// counted from 0 row by row. errors.Is(err, ErrBadDigit{}) matches any of
+// them.
+type ErrBadDigit struct {
+	Pos   int
+	Value int
+	// The largest value of the board, its number of rows.
+	Max int
+}
+
+func (e ErrBadDigit) Error() string {
+	return fmt.Sprintf("Number %d is not between 0 and %d at position: %d", e.Value, e.Max, e.Pos)
+}
+
+// Returns true if target is an ErrBadDigit with the same fields, or the zero
+// ErrBadDigit.
+func (e ErrBadDigit) Is(target error) bool {
+	t, ok := target.(ErrBadDigit)
+	return ok && (t == ErrBadDigit{} || t == e)
+}
+
+// Returns an empty board with boxes of boxRows rows and boxColumns columns,
+// ie. NewBoard(4, 3) for a 12x12 board with tall boxes, and the variant with
+// those boxes to solve, validate and print it with. Returns an error if the
+// board would have more than 31 rows, or a size without boxes of at least 2
+// rows, ie. 5x5.
+//
+// Without the variant, boxes are as square as possible with the wider side
+// along the rows, ie. 2x3 for 6x6 boards and 3x4 for 12x12 boards.
+func NewBoard(boxRows int, boxColumns int) (Board, Variant, error) {
+	size := boxRows * boxColumns
+	if boxRows < 1 || boxColumns < 1 || size > maxSize {
+		error := fmt.Sprintf("Unsupported box dimensions: %dx%d", boxRows, boxColumns)
+		return nil, Variant{}, errors.New(error)
 	}
+	board := make(Board, size*size)
+	s, err := board.shape()
+	if err != nil {
+		return nil, Variant{}, err
+	}
+	variant := Variant{}
+	if s.boxRows != boxRows {
+		variant.BoxRows, variant.BoxColumns = boxRows, boxColumns
+	}
+	return board, variant, nil
+}
 
-	// Validate that the numbers are 0-9.
-	for i, val := range b {
-		if val < 0 || val > 9 {
-			error := fmt.Sprintf(
-				"Internal number is not between 0 and 9 at position: %s",
-				i)
-			return false, errors.New(error)
+// Returns the shape of boards with the given number of cells, with their
+// default boxes, false if the number is not the square of a size with boxes
+// of at least 2 rows.
+func shapeOf(cells int) (*shape, bool) {
+	shapesLock.RLock()
+	s, ok := shapes[cells]
+	shapesLock.RUnlock()
+	if ok {
+		return s, true
+	}
+
+	size := 2
+	for size*size < cells {
+		size++
+	}
+	if size*size != cells || size > maxSize {
+		return nil, false
+	}
+	// The squarest boxes, with no more rows than columns.
+	boxRows := 0
+	for rows := 2; rows*rows <= size; rows++ {
+		if size%rows == 0 {
+			boxRows = rows
 		}
 	}
+	if boxRows == 0 {
+		return nil, false
+	}
 
-	return true, nil
+	shapesLock.Lock()
+	defer shapesLock.Unlock()
+	if s, ok := shapes[cells]; ok {
+		return s, true
+	}
+	s = newShape(boxRows, size/boxRows)
+	shapes[cells] = s
+	return s, true
 }
 
-// A pretty string repressenting the board.
-func (b Board) String() string {
-	buffer := bytes.NewBufferString("")
-	for y := 0; y < 9; y++ {
-		if y > 0 && y%3 == 0 {
-			buffer.WriteString("---+---+---\n")
+func newShape(boxRows int, boxColumns int) *shape {
+	size := boxRows * boxColumns
+	s := &shape{size: size, boxRows: boxRows, boxColumns: boxColumns, boxName: "box"}
+	s.link(func(y int, x int) int {
+		return (y/boxRows)*(size/boxColumns) + x/boxColumns
+	})
+	return s
+}
+
+// Fills in the units and peers of the shape, with the box of each cell from
+// box.
+func (s *shape) link(box func(y int, x int) int) {
+	size := s.size
+	s.unitCells = make([][]int, 3*size)
+	for pos := 0; pos < size*size; pos++ {
+		y, x := pos/size, pos%size
+		s.units = append(s.units, [3]int{y, x, box(y, x)})
+		for kind, unit := range s.units[pos] {
+			s.unitCells[kind*size+unit] = append(s.unitCells[kind*size+unit], pos)
 		}
-		for x := 0; x < 9; x++ {
-			if x > 0 && x%3 == 0 {
-				buffer.WriteString("|")
-			}
-			i := b[y*9+x]
-			if i == 0 {
-				buffer.WriteString(".")
-			} else {
-				buffer.WriteString(fmt.Sprintf("%d", i))
+	}
+	s.extraUnits = make([][]int, size*size)
+	s.cellConstraints = make([][]int, size*size)
+	s.linkPeers()
+}
+
+// Fills in the peers of each cell from its units.
+func (s *shape) linkPeers() {
+	s.peers = make([][]int, s.size*s.size)
+	for pos := range s.peers {
+		seen := map[int]bool{pos: true}
+		units := []int{s.unit(pos, 0), s.unit(pos, 1), s.unit(pos, 2)}
+		for _, extra := range s.extraUnits[pos] {
+			units = append(units, 3*s.size+extra)
+		}
+		for _, unit := range units {
+			for _, peer := range s.unitCells[unit] {
+				if !seen[peer] {
+					seen[peer] = true
+					s.peers[pos] = append(s.peers[pos], peer)
+				}
 			}
 		}
-		if y < 8 {
-			buffer.WriteString("\n")
+	}
+}
+
+// Returns a copy of the shape with extra units, ie. the diagonals, named
+// for errors.
+func (s *shape) withUnits(names []string, units [][]int) *shape {
+	result := *s
+	result.unitCells = append(append([][]int(nil), s.unitCells...), units...)
+	result.extraNames = append(append([]string(nil), s.extraNames...), names...)
+	result.extraUnits = make([][]int, len(s.extraUnits))
+	for pos := range result.extraUnits {
+		result.extraUnits[pos] = append([]int(nil), s.extraUnits[pos]...)
+	}
+	for i, cells := range units {
+		for _, pos := range cells {
+			result.extraUnits[pos] = append(result.extraUnits[pos], len(s.extraNames)+i)
 		}
 	}
-	output, _ := ioutil.ReadAll(buffer)
-	return string(output)
+	result.linkPeers()
+	return &result
 }
 
-// Solves the board, returns a solved board, or nil if the board cannot be solved.
-func (b Board) Solve() Board {
-	// Validate the board.
-	_, err := b.IsValid()
-	if err != nil {
-		return nil
+// Returns the shape of the board, or an error if it has an unsupported
+// number of cells.
+func (b Board) shape() (*shape, error) {
+	s, ok := shapeOf(len(b))
+	if !ok {
+		return nil, ErrBadSize
 	}
+	return s, nil
+}
+
+// Returns the index in unitCells of the row (kind 0), column (1) or box (2)
+// containing the cell.
+func (s *shape) unit(pos int, kind int) int {
+	return kind*s.size + s.units[pos][kind]
+}
 
-	// Solve using backtrack
-	return b.backtrack(b, 0, 0)
+// Returns the cell at column x of row y.
+func (s *shape) cell(y int, x int) int {
+	return y*s.size + x
 }
 
-func (b Board) deepcopy(board Board) Board {
-	result := make(Board, 81)
-	copy(result, board)
-	return result
+// Returns the cell at index i of box.
+func (s *shape) boxCell(box int, i int) int {
+	return s.unitCells[2*s.size+box][i]
 }
 
-func (b Board) backtrack(board Board, x int, y int) Board {
-	board = b.deepcopy(board)
+// Returns the character of a value in the line format: 1-9, then A-Z.
+func valueChar(val int) byte {
+	if val < 10 {
+		return byte('0' + val)
+	}
+	return byte('A' + val - 10)
+}
+
+// Returns true/false, and an error if the board is not valid: a
+// ValidationReport of every problem, or an error if the board has an
+// unsupported size.
+func (b Board) IsValid() (bool, error) {
+	// Validate the length of the board.
+	s, err := b.shape()
+	if err != nil {
+		return false, err
+	}
+	return b.isValid(s)
+}
 
-	// Skip positions with existing data.
-	if board[y*9+x] != 0 {
-		return b.next(board, x, y)
+// Returns true/false, and a ValidationReport of every problem as the error
+// if the board is not valid for the shape, which must have as many cells as
+// the board.
+func (b Board) isValid(s *shape) (bool, error) {
+	// Validate that the numbers are in range.
+	if err := b.checkRange(s); err != nil {
+		return false, b.report(s)
 	}
 
-	// Iterate on possible solutions.
-	for i := 1; i <= 9; i++ {
-		if !b.check(board, i, x, y) {
-			continue
+	// Validate that no number is given twice in a row, column or box, or
+	// in the extra units of a variant.
+	for _, cells := range s.unitCells {
+		var seen [maxSize + 1]bool
+		for _, pos := range cells {
+			val := b[pos]
+			if val != 0 && seen[val] {
+				return false, b.report(s)
+			}
+			seen[val] = true
 		}
-		board[y*9+x] = i
-		result := b.next(board, x, y)
-		if result != nil {
-			return result
+	}
+
+	// And the other rules of a variant.
+	for _, c := range s.constraints {
+		if err := c.check(b); err != nil {
+			return false, b.report(s)
 		}
 	}
 
-	// No solution found.
-	return nil
+	return true, nil
 }
 
-func (b Board) next(board Board, x int, y int) Board {
-	if x == 8 {
-		if y == 8 {
-			return board
+// Returns an error if a value of the board is not between 0 and the size
+// of the shape.
+func (b Board) checkRange(s *shape) error {
+	for i, val := range b {
+		if val < 0 || val > s.size {
+			return ErrBadDigit{Pos: i, Value: val, Max: s.size}
 		}
-		return b.backtrack(board, 0, y+1)
-	} else {
-		return b.backtrack(board, x+1, y)
 	}
+	return nil
 }
 
-//
-func (b Board) check(board Board, val int, x int, y int) bool {
-	// Validate horizontal.
-	for _x := 0; _x < 9; _x++ {
-		if _x != x {
-			if board[y*9+_x] == val {
-				return false
-			}
-		}
+// Returns the board as rows of numbers, ie. for nested json output.
+func (b Board) Rows() [][]int {
+	size := boardSize(len(b))
+	rows := make([][]int, size)
+	for y := range rows {
+		rows[y] = append([]int(nil), b[y*size:y*size+size]...)
 	}
+	return rows
+}
 
-	// Validate vertical.
-	for _y := 0; _y < 9; _y++ {
-		if _y != y {
-			if board[_y*9+x] == val {
-				return false
-			}
-		}
+// Returns the number of cells in each row of a board with the given number
+// of cells.
+func boardSize(cells int) int {
+	if s, ok := shapeOf(cells); ok {
+		return s.size
 	}
+	return 9
+}
 
-	// check the current box.
-	ybox := (y / 3) * 3
-	xbox := (x / 3) * 3
-	for _x := xbox; _x < xbox+3; _x++ {
-		for _y := ybox; _y < ybox+3; _y++ {
-			if _y != y || _x != x {
-				if board[_y*9+_x] == val {
-					return false
-				}
-			}
+// Returns the board in the line format, a character per cell (ie. 81 for a 9x9
+// board), with '.' for blanks and A-Z for 10 and up.
+func (b Board) Line() string {
+	line := make([]byte, len(b))
+	for i, val := range b {
+		if val == 0 {
+			line[i] = '.'
+		} else {
+			line[i] = valueChar(val)
 		}
 	}
+	return string(line)
+}
 
-	return true
+// A pretty string repressenting the board.
+func (b Board) String() string {
+	return b.Pretty(ASCII)
 }
 
-// Read from stdin. Write to stdout, or stderr and return non-0 return code.
-func main() {
-	// Read stdin.
-	bytes, err := ioutil.ReadAll(os.Stdin)
-	if err != nil {
-		fmt.Fprint(os.Stderr, err)
-		os.Exit(1)
+// The characters drawing the boxes of a pretty board.
+type Style int
+
+const (
+	// '|', '-' and '+' between the boxes, see Board.String.
+	ASCII Style = iota
+	// Box drawing characters around and between the boxes, with a space
+	// before each cell, which lines up in more fonts.
+	Unicode
+)
+
+// Returns the board like String, drawn in the style.
+func (b Board) Pretty(style Style) string {
+	return Variant{}.PrettyStyle(b, style)
+}
+
+// Returns the board like Pretty with the boxes of s, with each cell followed
+// by its mark if marks is not nil, or a space for the cells without one.
+func (b Board) pretty(s *shape, marks map[int]byte, style Style) string {
+	if style == Unicode {
+		return b.boxDrawing(s, marks)
 	}
-	if len(bytes) == 0 {
-		fmt.Fprintln(os.Stderr, "No input")
-		os.Exit(1)
+	width := s.boxColumns
+	if marks != nil {
+		width *= 2
 	}
+	separator := strings.Repeat(strings.Repeat("-", width)+"+", s.size/s.boxColumns)
+	separator = separator[:len(separator)-1] + "\n"
 
-	// Parse json.
-	board := Board{}
-	err = json.Unmarshal(bytes, &board)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	buffer := bytes.NewBufferString("")
+	for y := 0; y < s.size; y++ {
+		if y > 0 && y%s.boxRows == 0 {
+			buffer.WriteString(separator)
+		}
+		for x := 0; x < s.size; x++ {
+			if x > 0 && x%s.boxColumns == 0 {
+				buffer.WriteString("|")
+			}
+			i := b[s.cell(y, x)]
+			if i == 0 {
+				buffer.WriteString(".")
+			} else {
+				buffer.WriteByte(valueChar(i))
+			}
+			if mark, ok := marks[s.cell(y, x)]; ok {
+				buffer.WriteByte(mark)
+			} else if marks != nil {
+				buffer.WriteByte(' ')
+			}
+		}
+		if y < s.size-1 {
+			buffer.WriteString("\n")
+		}
 	}
+	output, _ := ioutil.ReadAll(buffer)
+	return string(output)
+}
 
-	// Validate that board is valid.
-	_, err = board.IsValid()
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+// Returns the board like pretty, drawn with box drawing characters.
+func (b Board) boxDrawing(s *shape, marks map[int]byte) string {
+	width := 2*s.boxColumns + 1
+	if marks != nil {
+		width += s.boxColumns
+	}
+	line := func(left string, middle string, right string) string {
+		box := strings.Repeat("─", width)
+		return left + strings.Repeat(box+middle, s.size/s.boxColumns-1) + box + right + "\n"
 	}
 
-	// solve, or fail.
-	board = board.Solve()
-
-	// write the result.
-	result, err := json.Marshal(board)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	buffer := bytes.NewBufferString(line("┌", "┬", "┐"))
+	for y := 0; y < s.size; y++ {
+		if y > 0 && y%s.boxRows == 0 {
+			buffer.WriteString(line("├", "┼", "┤"))
+		}
+		for x := 0; x < s.size; x++ {
+			if x%s.boxColumns == 0 {
+				buffer.WriteString("│")
+			}
+			buffer.WriteByte(' ')
+			if val := b[s.cell(y, x)]; val == 0 {
+				buffer.WriteByte('.')
+			} else {
+				buffer.WriteByte(valueChar(val))
+			}
+			if mark, ok := marks[s.cell(y, x)]; ok {
+				buffer.WriteByte(mark)
+			} else if marks != nil {
+				buffer.WriteByte(' ')
+			}
+			if x%s.boxColumns == s.boxColumns-1 {
+				buffer.WriteByte(' ')
+			}
+		}
+		buffer.WriteString("│\n")
 	}
-	fmt.Printf("%s\n", result)
+	buffer.WriteString(strings.TrimSuffix(line("└", "┴", "┘"), "\n"))
+	return buffer.String()
 }
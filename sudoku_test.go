@@ -0,0 +1,76 @@
+package sudoku
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewBoard(t *testing.T) {
+	tests := []struct {
+		boxRows, boxColumns int
+		variant             Variant
+		invalid             bool
+	}{
+		{3, 3, Variant{}, false},
+		{2, 3, Variant{}, false},
+		{3, 2, Variant{BoxRows: 3, BoxColumns: 2}, false},
+		{4, 3, Variant{BoxRows: 4, BoxColumns: 3}, false},
+		{2, 6, Variant{BoxRows: 2, BoxColumns: 6}, false},
+		{0, 3, Variant{}, true},
+		{4, 8, Variant{}, true},
+		{1, 5, Variant{}, true},
+	}
+	for _, test := range tests {
+		board, variant, err := NewBoard(test.boxRows, test.boxColumns)
+		if test.invalid {
+			if err == nil {
+				t.Errorf("%dx%d: got no error", test.boxRows, test.boxColumns)
+			}
+			continue
+		}
+		size := test.boxRows * test.boxColumns
+		if err != nil || len(board) != size*size || variant.BoxRows != test.variant.BoxRows || variant.BoxColumns != test.variant.BoxColumns {
+			t.Errorf("%dx%d: got %d cells, %+v, %v", test.boxRows, test.boxColumns, len(board), variant, err)
+		}
+	}
+}
+
+func TestVariantBoxes(t *testing.T) {
+	board, tall, err := NewBoard(4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := Solver{Variant: tall}.Solve(context.Background(), board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newShape(4, 3)
+	for _, cells := range s.unitCells {
+		seen := map[int]bool{}
+		for _, pos := range cells {
+			if seen[solution[pos]] {
+				t.Fatalf("got %d twice in a unit of 4x3 boxes:\n%s", solution[pos], tall.Pretty(solution))
+			}
+			seen[solution[pos]] = true
+		}
+	}
+	if lines := strings.Split(tall.Pretty(solution), "\n"); lines[4] != "---+---+---+---" {
+		t.Errorf("got %q after the first band of the 4x3 boxes", lines[4])
+	}
+
+	// Boards of the same size keep their default boxes.
+	if _, err := make(Board, 144).Solve(); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(solution.String(), "\n"); lines[3] != "----+----+----" {
+		t.Errorf("got %q after the first band of the default 3x4 boxes", lines[3])
+	}
+	if valid, _ := solution.IsValid(); valid {
+		t.Error("got the solution with 4x3 boxes valid with the default boxes")
+	}
+
+	if _, err := (Variant{BoxRows: 3, BoxColumns: 3}).IsValid(board); err == nil {
+		t.Error("got no error for 3x3 boxes on a 12x12 board")
+	}
+}
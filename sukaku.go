@@ -0,0 +1,80 @@
+package sudoku
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Parses a sukaku, a puzzle given as the candidates of each cell instead of
+// givens, see Variant.ParseSukaku.
+func ParseSukaku(data []byte) (Board, Variant, error) {
+	return Variant{}.ParseSukaku(data)
+}
+
+// Parses a sukaku as the 729 characters of the candidates of each cell, row
+// by row: 9 characters per cell, the value for each candidate and '.' or '0'
+// in its place otherwise, ie. "1.3...7.9" for a cell with the candidates 1,
+// 3, 7 and 9 (or size characters per cell for the other sizes, with A-Z for
+// 10 and up). Whitespace is ignored. Returns an empty board and the variant
+// with the candidates added to v.
+func (v Variant) ParseSukaku(data []byte) (Board, Variant, error) {
+	text := make([]byte, 0, len(data))
+	for _, c := range data {
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			text = append(text, c)
+		}
+	}
+	if len(text) == 0 {
+		return nil, v, errors.New("No input")
+	}
+
+	size := 2
+	for size*size*size < len(text) {
+		size++
+	}
+	s, ok := shapeOf(size * size)
+	if !ok || size*size*size != len(text) {
+		return nil, v, fmt.Errorf("%w: the sukaku is %d characters long, expected the size cubed", ErrBadSize, len(text))
+	}
+
+	candidates := make([][]int, s.size*s.size)
+	for i, c := range text {
+		pos, val := i/s.size, i%s.size+1
+		switch {
+		case c == '.' || c == '0':
+		case c == valueChar(val) || val > 9 && c == valueChar(val)|0x20:
+			candidates[pos] = append(candidates[pos], val)
+		default:
+			error := fmt.Sprintf("Unexpected character %q at position: %d", c, i)
+			return nil, v, errors.New(error)
+		}
+	}
+	for pos := range candidates {
+		if candidates[pos] == nil {
+			candidates[pos] = []int{}
+		}
+	}
+
+	v.Candidates = candidates
+	board := make(Board, len(candidates))
+	if _, err := v.IsValid(board); err != nil {
+		return nil, v, fmt.Errorf("%w: %w", ErrInvalidBoard, err)
+	}
+	return board, v, nil
+}
+
+// Returns the board as a sukaku, the values of filled cells as their only
+// candidate and every value as the candidates of the empty ones.
+func (b Board) Sukaku() string {
+	size := boardSize(len(b))
+	text := bytes.Repeat([]byte("."), len(b)*size)
+	for pos, val := range b {
+		for v := 1; v <= size; v++ {
+			if val == 0 || val == v {
+				text[pos*size+v-1] = valueChar(v)
+			}
+		}
+	}
+	return string(text)
+}
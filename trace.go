@@ -0,0 +1,63 @@
+package sudoku
+
+import (
+	"fmt"
+)
+
+// The kind of a TraceStep.
+type TraceKind int
+
+const (
+	// A value was placed in a cell, as its only candidate left or as a
+	// guess on a branch of the search.
+	TraceAssign TraceKind = iota
+	// A candidate of a cell was ruled out by a rule of the variant beyond
+	// its units, ie. a killer cage. Only reported when it leaves the cell
+	// with a single candidate or none.
+	TraceEliminate
+	// An empty cell has no candidates left, so the branch is given up.
+	TraceDeadEnd
+	// A guessed value was removed again, to try the next candidate.
+	TraceBacktrack
+)
+
+// Encodes the kind as "assign", "eliminate", "dead end" or "backtrack", ie.
+// in json.
+func (k TraceKind) MarshalText() ([]byte, error) {
+	switch k {
+	case TraceAssign:
+		return []byte("assign"), nil
+	case TraceEliminate:
+		return []byte("eliminate"), nil
+	case TraceDeadEnd:
+		return []byte("dead end"), nil
+	case TraceBacktrack:
+		return []byte("backtrack"), nil
+	}
+	return nil, fmt.Errorf("Unknown trace kind: %d", int(k))
+}
+
+// A step of the search, reported to Solver.Trace.
+type TraceStep struct {
+	Kind  TraceKind  `json:"type"`
+	Cell  Coordinate `json:"cell"`
+	Value int        `json:"value,omitempty"`
+	// The number of guesses the step is nested in.
+	Depth int `json:"depth"`
+	// Why the step was taken: "single" or "guess" for an assignment, and
+	// the rule for an elimination, ie. "cage 3".
+	Reason string `json:"reason,omitempty"`
+}
+
+// Describes the step, ie. "r1c3 = 5 (single)" or "r2c4 ≠ 7 (cage 3)".
+func (t TraceStep) String() string {
+	switch t.Kind {
+	case TraceAssign:
+		return fmt.Sprintf("%s = %d (%s)", t.Cell, t.Value, t.Reason)
+	case TraceEliminate:
+		return fmt.Sprintf("%s ≠ %d (%s)", t.Cell, t.Value, t.Reason)
+	case TraceDeadEnd:
+		return fmt.Sprintf("%s has no candidates", t.Cell)
+	}
+	return fmt.Sprintf("%s = %d undone", t.Cell, t.Value)
+}
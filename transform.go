@@ -0,0 +1,235 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A rearrangement of the cells and values of a board which keeps a valid
+// board valid and its solutions solutions, ie. a rotation. Any combination
+// of rotations, reflections, swaps of bands, stacks, rows within a band and
+// columns within a stack and relabelings of the values is one transform:
+// the board is transposed if Transpose is set, then row i is taken from
+// row Rows[i], column j from column Columns[j], and value v becomes
+// Values[v].
+//
+// Transforms are made by chaining from Identity, ie.
+// Identity(9).Rotate().SwapBands(3, 0, 2), and applied with Apply.
+type Transform struct {
+	Transpose bool  `json:"transpose,omitempty"`
+	Rows      []int `json:"rows"`
+	Columns   []int `json:"columns"`
+	// The new value of each value, Values[0] is always 0.
+	Values []int `json:"values"`
+}
+
+// Returns the transform leaving a board of size rows as it is.
+func Identity(size int) Transform {
+	t := Transform{Rows: make([]int, size), Columns: make([]int, size), Values: make([]int, size+1)}
+	for i := 0; i < size; i++ {
+		t.Rows[i], t.Columns[i] = i, i
+	}
+	for v := range t.Values {
+		t.Values[v] = v
+	}
+	return t
+}
+
+// Returns the transform doing t and then u, which must be for the same
+// size of board.
+func (t Transform) Then(u Transform) Transform {
+	result := Transform{Transpose: t.Transpose != u.Transpose, Rows: make([]int, len(t.Rows)),
+		Columns: make([]int, len(t.Columns)), Values: make([]int, len(t.Values))}
+	rows, columns := t.Rows, t.Columns
+	if u.Transpose {
+		rows, columns = columns, rows
+	}
+	for i := range result.Rows {
+		result.Rows[i] = rows[u.Rows[i]]
+		result.Columns[i] = columns[u.Columns[i]]
+	}
+	for v := range result.Values {
+		result.Values[v] = u.Values[t.Values[v]]
+	}
+	return result
+}
+
+// Returns the transform undoing t.
+func (t Transform) Inverse() Transform {
+	result := Transform{Transpose: t.Transpose, Rows: make([]int, len(t.Rows)),
+		Columns: make([]int, len(t.Columns)), Values: make([]int, len(t.Values))}
+	rows, columns := result.Rows, result.Columns
+	if t.Transpose {
+		rows, columns = columns, rows
+	}
+	for i := range t.Rows {
+		rows[t.Rows[i]] = i
+		columns[t.Columns[i]] = i
+	}
+	for v, val := range t.Values {
+		result.Values[val] = v
+	}
+	return result
+}
+
+// Returns t then a reflection in the main diagonal, the first row becoming
+// the first column. Boards with boxes that are not square, ie. 6x6, cannot
+// be transposed.
+func (t Transform) Transposed() Transform {
+	u := Identity(len(t.Rows))
+	u.Transpose = true
+	return t.Then(u)
+}
+
+// Returns t then a quarter turn clockwise, the first row becoming the last
+// column.
+func (t Transform) Rotate() Transform {
+	u := Identity(len(t.Rows))
+	u.Transpose = true
+	reverse(u.Columns)
+	return t.Then(u)
+}
+
+// Returns t then a reflection left to right, the first column becoming the
+// last.
+func (t Transform) MirrorColumns() Transform {
+	u := Identity(len(t.Rows))
+	reverse(u.Columns)
+	return t.Then(u)
+}
+
+// Returns t then a reflection top to bottom, the first row becoming the
+// last.
+func (t Transform) MirrorRows() Transform {
+	u := Identity(len(t.Rows))
+	reverse(u.Rows)
+	return t.Then(u)
+}
+
+// Returns t then a swap of rows a and b, counted from 0, which must be in
+// the same band.
+func (t Transform) SwapRows(a int, b int) Transform {
+	u := Identity(len(t.Rows))
+	u.Rows[a], u.Rows[b] = b, a
+	return t.Then(u)
+}
+
+// Returns t then a swap of columns a and b, counted from 0, which must be in
+// the same stack.
+func (t Transform) SwapColumns(a int, b int) Transform {
+	u := Identity(len(t.Rows))
+	u.Columns[a], u.Columns[b] = b, a
+	return t.Then(u)
+}
+
+// Returns t then a swap of bands a and b, the rows of boxes counted from 0,
+// on a board with boxes of boxRows rows.
+func (t Transform) SwapBands(boxRows int, a int, b int) Transform {
+	u := Identity(len(t.Rows))
+	for i := 0; i < boxRows; i++ {
+		u.Rows[a*boxRows+i], u.Rows[b*boxRows+i] = b*boxRows+i, a*boxRows+i
+	}
+	return t.Then(u)
+}
+
+// Returns t then a swap of stacks a and b, the columns of boxes counted from
+// 0, on a board with boxes of boxColumns columns.
+func (t Transform) SwapStacks(boxColumns int, a int, b int) Transform {
+	u := Identity(len(t.Rows))
+	for i := 0; i < boxColumns; i++ {
+		u.Columns[a*boxColumns+i], u.Columns[b*boxColumns+i] = b*boxColumns+i, a*boxColumns+i
+	}
+	return t.Then(u)
+}
+
+// Returns t then a relabeling of the values, value v becoming values[v-1],
+// ie. []int{2, 1, 3, ...} swaps 1 and 2.
+func (t Transform) Relabel(values []int) Transform {
+	u := Identity(len(t.Rows))
+	copy(u.Values[1:], values)
+	return t.Then(u)
+}
+
+// Reverses a slice in place.
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// Returns the transformed board, or an error if the transform is not for a
+// board of its size or would not keep its boxes.
+func (t Transform) Apply(b Board) (Board, error) {
+	s, err := b.shape()
+	if err != nil {
+		return nil, err
+	}
+	if err := t.check(s); err != nil {
+		return nil, err
+	}
+	if err := b.checkRange(s); err != nil {
+		return nil, err
+	}
+	result := make(Board, len(b))
+	for i := 0; i < s.size; i++ {
+		for j := 0; j < s.size; j++ {
+			y, x := t.Rows[i], t.Columns[j]
+			if t.Transpose {
+				y, x = x, y
+			}
+			result[s.cell(i, j)] = t.Values[b[s.cell(y, x)]]
+		}
+	}
+	return result, nil
+}
+
+// Returns an error if the transform is not for a board of shape s, or
+// would not keep its boxes.
+func (t Transform) check(s *shape) error {
+	if len(t.Rows) != s.size || len(t.Columns) != s.size || len(t.Values) != s.size+1 {
+		error := fmt.Sprintf("Transform is for %d rows, expected %d", len(t.Rows), s.size)
+		return errors.New(error)
+	}
+	if t.Transpose && s.boxRows != s.boxColumns {
+		error := fmt.Sprintf("Boards with %dx%d boxes cannot be transposed", s.boxRows, s.boxColumns)
+		return errors.New(error)
+	}
+	if err := checkLines(t.Rows, s.boxRows, "row", "band"); err != nil {
+		return err
+	}
+	if err := checkLines(t.Columns, s.boxColumns, "column", "stack"); err != nil {
+		return err
+	}
+	if t.Values[0] != 0 || !isPermutation(t.Values[1:], 1) {
+		return errors.New("Transform values are not a relabeling of the values")
+	}
+	return nil
+}
+
+// Returns an error if lines is not a permutation moving the lines of each
+// group of size lines, ie. a band, together.
+func checkLines(lines []int, size int, name string, group string) error {
+	if !isPermutation(lines, 0) {
+		error := fmt.Sprintf("Transform %ss are not a permutation", name)
+		return errors.New(error)
+	}
+	for i, line := range lines {
+		if line/size != lines[i-i%size]/size {
+			error := fmt.Sprintf("Transform moves %s %d out of its %s", name, line+1, group)
+			return errors.New(error)
+		}
+	}
+	return nil
+}
+
+// Returns true if s has every number from first to first+len(s)-1 once.
+func isPermutation(s []int, first int) bool {
+	seen := make([]bool, len(s))
+	for _, v := range s {
+		if v < first || v-first >= len(s) || seen[v-first] {
+			return false
+		}
+		seen[v-first] = true
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package sudoku
+
+import (
+	"testing"
+)
+
+// A solved 4x4 board, whose rows are 1234, 3412, 2143 and 4321.
+const smallSolution = "1234341221434321"
+
+func TestTransform(t *testing.T) {
+	board, err := ParseLine(smallSolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		transform Transform
+		want      string
+	}{
+		{"identity", Identity(4), smallSolution},
+		{"rotate", Identity(4).Rotate(), "4231314224131324"},
+		{"rotate twice", Identity(4).Rotate().Then(Identity(4).Rotate()), "1234341221434321"},
+		{"transpose", Identity(4).Transposed(), "1324241331424231"},
+		{"mirror columns", Identity(4).MirrorColumns(), "4321214334121234"},
+		{"mirror rows", Identity(4).MirrorRows(), "4321214334121234"},
+		{"swap rows", Identity(4).SwapRows(0, 1), "3412123421434321"},
+		{"swap columns", Identity(4).SwapColumns(2, 3), "1243342121344312"},
+		{"swap bands", Identity(4).SwapBands(2, 0, 1), "2143432112343412"},
+		{"swap stacks", Identity(4).SwapStacks(2, 0, 1), "3412123443212143"},
+		{"relabel", Identity(4).Relabel([]int{2, 1, 4, 3}), "2143432112343412"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transformed, err := test.transform.Apply(board)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transformed.Line() != test.want {
+				t.Errorf("got %s, want %s", transformed.Line(), test.want)
+			}
+			if _, err := transformed.IsValid(); err != nil {
+				t.Errorf("got %v, want a valid board", err)
+			}
+			back, err := test.transform.Inverse().Apply(transformed)
+			if err != nil || back.Line() != smallSolution {
+				t.Errorf("got %s, %v undoing the transform, want %s", back.Line(), err, smallSolution)
+			}
+		})
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+		board     Board
+	}{
+		{"other size", Identity(6), make(Board, 16)},
+		{"transpose 2x3 boxes", Identity(6).Transposed(), make(Board, 36)},
+		{"row out of its band", Identity(4).SwapRows(1, 2), make(Board, 16)},
+		{"column out of its stack", Identity(4).SwapColumns(1, 2), make(Board, 16)},
+		{"not a relabeling", Identity(4).Relabel([]int{1, 1, 2, 3}), make(Board, 16)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.transform.Apply(test.board); err == nil {
+				t.Error("got no error")
+			}
+		})
+	}
+}
+
+func TestTransformInverse(t *testing.T) {
+	board, err := ParseLine(easyLine)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transform := Identity(9).Rotate().SwapStacks(3, 0, 2).SwapColumns(3, 4).Relabel([]int{3, 1, 2, 4, 5, 6, 7, 8, 9})
+	transformed, err := transform.Apply(board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transformed.Line() == board.Line() {
+		t.Fatal("the transform left the board as it is")
+	}
+	back, err := transform.Inverse().Apply(transformed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Line() != board.Line() {
+		t.Errorf("got %s undoing the transform, want %s", back.Line(), board.Line())
+	}
+	if _, err := Identity(9).SwapRows(0, 3).Apply(board); err == nil {
+		t.Error("got no error swapping rows of different bands")
+	}
+	if _, err := Identity(4).Apply(board); err == nil {
+		t.Error("got no error applying a transform for another size")
+	}
+}
@@ -0,0 +1,112 @@
+package sudoku
+
+import (
+	"fmt"
+)
+
+// Something wrong with a board, ie. a value twice in a row, and the cells
+// causing it.
+type Problem struct {
+	Cells   []Coordinate `json:"cells"`
+	Message string       `json:"message"`
+	// The error for the problem, an ErrBadDigit, an ErrConflict or the
+	// error of another rule of the variant.
+	err error
+}
+
+// Every problem of a board, ie. to highlight everything wrong with it at
+// once. Boards that are not valid fail with a report as their error (wrapped
+// in ErrInvalidBoard by Parse and the solvers), which errors.As finds, and
+// errors.Is and errors.As also find the error of each problem.
+type ValidationReport struct {
+	Problems []Problem `json:"problems"`
+}
+
+// Returns true if the board has no problems.
+func (r ValidationReport) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// Returns the message of the first problem, and the number of others.
+func (r ValidationReport) Error() string {
+	switch len(r.Problems) {
+	case 0:
+		return "Board is valid"
+	case 1:
+		return r.Problems[0].Message
+	}
+	return fmt.Sprintf("%s (and %d more problems)", r.Problems[0].Message, len(r.Problems)-1)
+}
+
+// Returns the errors of the problems.
+func (r ValidationReport) Unwrap() []error {
+	errs := make([]error, len(r.Problems))
+	for i, p := range r.Problems {
+		errs[i] = p.err
+	}
+	return errs
+}
+
+// Returns every problem of the board: the values out of range, the pairs of
+// cells breaking a rule together (see Conflicts) and the other rules broken.
+// Returns an error if the board has an unsupported size.
+func (b Board) Validate() (ValidationReport, error) {
+	s, err := b.shape()
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	return b.report(s), nil
+}
+
+// Returns every problem of the board under the variant's rules, like
+// Board.Validate. Returns an error if the rules are not valid for the board.
+func (v Variant) Validate(b Board) (ValidationReport, error) {
+	s, err := b.shape()
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	s, err = v.shape(s)
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	return b.report(s), nil
+}
+
+// Returns every problem of the board, which has the shape s. The values out
+// of range are left out of the other checks.
+func (b Board) report(s *shape) ValidationReport {
+	report := ValidationReport{}
+	add := func(err error, cells ...int) {
+		p := Problem{Cells: make([]Coordinate, len(cells)), Message: err.Error(), err: err}
+		for i, pos := range cells {
+			p.Cells[i] = Coordinate{pos / s.size, pos % s.size}
+		}
+		report.Problems = append(report.Problems, p)
+	}
+
+	board, copied := b, false
+	for pos, val := range b {
+		if val < 0 || val > s.size {
+			if !copied {
+				board, copied = append(Board(nil), b...), true
+			}
+			board[pos] = 0
+			add(ErrBadDigit{Pos: pos, Value: val, Max: s.size}, pos)
+		}
+	}
+
+	conflicts, _ := board.conflicts(s)
+	for _, c := range conflicts {
+		a, other := c.Cells[0], c.Cells[1]
+		add(ErrConflict{a, other, c.Rule}, a.Row*s.size+a.Column, other.Row*s.size+other.Column)
+	}
+	for _, c := range s.constraints {
+		if _, ok := c.(*pairRule); ok {
+			continue
+		}
+		if err := c.check(board); err != nil {
+			add(err, c.cells()...)
+		}
+	}
+	return report
+}
@@ -0,0 +1,653 @@
+package sudoku
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// The rules of a sudoku variant, replacing or adding to the classic rules.
+// The zero value is classic sudoku.
+type Variant struct {
+	// The rows and columns of each box, replacing the boxes boards of the
+	// size have by default, ie. 4 and 3 for a 12x12 board with boxes
+	// taller than they are wide (see NewBoard). Both 0 for the default.
+	BoxRows    int `json:"boxrows,omitempty"`
+	BoxColumns int `json:"boxcolumns,omitempty"`
+	// The region of each cell, numbered from 0, replacing the boxes (ie.
+	// jigsaw sudoku). Each region has as many cells as a row, connected side
+	// by side.
+	Regions []int `json:"regions,omitempty"`
+	// Each value appears once on both main diagonals (ie. sudoku X).
+	Diagonals bool `json:"diagonals,omitempty"`
+	// Each value appears once in the extra boxes a cell in from the corners
+	// of the boxes, ie. the four shaded 3x3 windows of hyper sudoku (or
+	// windoku).
+	Windows bool `json:"windows,omitempty"`
+	// Each value appears once in the cells at the same position of every
+	// box, ie. the nine top left cells of the boxes (disjoint groups).
+	Disjoint bool `json:"disjoint,omitempty"`
+	// No value appears twice a chess knight's move apart.
+	AntiKnight bool `json:"antiknight,omitempty"`
+	// No value appears twice in diagonally adjacent cells, a chess king's
+	// move apart (the other king's moves stay in a row or column).
+	AntiKing bool `json:"antiking,omitempty"`
+	// Orthogonally adjacent cells never have consecutive values.
+	NonConsecutive bool `json:"nonconsecutive,omitempty"`
+	// The pairs of orthogonally adjacent cells marked X in XV sudoku, whose
+	// values add up to 10, and V, whose values add up to 5. With any marks,
+	// the values of the other adjacent cells add up to neither.
+	XMarks [][2]int `json:"x,omitempty"`
+	VMarks [][2]int `json:"v,omitempty"`
+	// The pairs of orthogonally adjacent cells with a white kropki dot, whose
+	// values are consecutive, and a black one, where one value is twice the
+	// other.
+	WhiteDots [][2]int `json:"white,omitempty"`
+	BlackDots [][2]int `json:"black,omitempty"`
+	// Every kropki dot is given, the values of the other adjacent cells are
+	// neither consecutive nor one twice the other. Generate places the dots
+	// of the solution, see GeneratePuzzle.
+	AllDots bool `json:"alldots,omitempty"`
+	// The thermometers, paths of cells from the bulb along which the values
+	// strictly increase. Each cell of a path is next to the previous one,
+	// side by side or diagonally.
+	Thermos [][]int `json:"thermos,omitempty"`
+	// The arrows, whose values add up to the value of their circle.
+	Arrows []Arrow `json:"arrows,omitempty"`
+	// The palindromes, paths of cells like the thermometers whose values read
+	// the same from either end.
+	Palindromes [][]int `json:"palindromes,omitempty"`
+	// The sandwich sums of each row and column, outside clues giving the sum
+	// of the values between the 1 and the largest value, -1 for a row or
+	// column without a clue. Each line is pruned on its own, so a puzzle
+	// with few or no given values can take very long to solve.
+	SandwichRows    []int `json:"sandwichrows,omitempty"`
+	SandwichColumns []int `json:"sandwichcolumns,omitempty"`
+	// The cages of killer sudoku.
+	Cages []Cage `json:"cages,omitempty"`
+	// The candidates of each cell, the values it can have (ie. sukaku, where
+	// the puzzle gives candidates instead of givens), null for any value.
+	Candidates [][]int `json:"candidates,omitempty"`
+}
+
+// An arrow of arrow sudoku, a path of cells from a circle whose values,
+// which may repeat, add up to the value of the circle.
+type Arrow struct {
+	// The cell of the circle, as an index row by row.
+	Circle int `json:"circle"`
+	// The cells of the arrow from the circle, each next to the previous one,
+	// side by side or diagonally.
+	Cells []int `json:"cells"`
+}
+
+// A cage of killer sudoku, a group of cells without repeated values that add
+// up to the sum (if it is not 0).
+type Cage struct {
+	// The cells of the cage, as indexes row by row.
+	Cells []int `json:"cells"`
+	Sum   int   `json:"sum"`
+}
+
+// Returns true/false, and an error if the board is not valid under the
+// variant's rules, or the rules are not valid for the board.
+func (v Variant) IsValid(b Board) (bool, error) {
+	_, err := v.validate(b)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Returns the shape of the board under the variant's rules, or an error if
+// the board is not valid.
+func (v Variant) validate(b Board) (*shape, error) {
+	s, err := b.shape()
+	if err != nil {
+		return nil, err
+	}
+	s, err = v.shape(s)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := b.isValid(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Returns the classic shape s with the variant's rules, or an error if they
+// are not valid for it.
+func (v Variant) shape(s *shape) (*shape, error) {
+	s, err := v.boxes(s)
+	if err != nil {
+		return nil, err
+	}
+	if v.Regions != nil {
+		s, err = regionShape(s, v.Regions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.Diagonals {
+		diagonals := [][]int{{}, {}}
+		for i := 0; i < s.size; i++ {
+			diagonals[0] = append(diagonals[0], s.cell(i, i))
+			diagonals[1] = append(diagonals[1], s.cell(i, s.size-1-i))
+		}
+		s = s.withUnits([]string{"diagonal 1", "diagonal 2"}, diagonals)
+	}
+	if v.Windows {
+		names, windows := []string{}, [][]int{}
+		for top := 1; top+s.boxRows < s.size; top += s.boxRows + 1 {
+			for left := 1; left+s.boxColumns < s.size; left += s.boxColumns + 1 {
+				window := []int{}
+				for i := 0; i < s.size; i++ {
+					window = append(window, s.cell(top+i/s.boxColumns, left+i%s.boxColumns))
+				}
+				names = append(names, fmt.Sprintf("window %d", len(windows)+1))
+				windows = append(windows, window)
+			}
+		}
+		s = s.withUnits(names, windows)
+	}
+	if v.Disjoint {
+		names, groups := []string{}, make([][]int, s.size)
+		for pos := 0; pos < s.size*s.size; pos++ {
+			y, x := pos/s.size, pos%s.size
+			group := y%s.boxRows*s.boxColumns + x%s.boxColumns
+			groups[group] = append(groups[group], pos)
+		}
+		for i := range groups {
+			names = append(names, fmt.Sprintf("disjoint group %d", i+1))
+		}
+		s = s.withUnits(names, groups)
+	}
+	if v.AntiKnight {
+		moves := [][2]int{{1, 2}, {2, 1}, {1, -2}, {2, -1}}
+		s = s.withUnits(pairUnits(s, moves, "a knight's move apart"))
+	}
+	if v.AntiKing {
+		moves := [][2]int{{1, 1}, {1, -1}}
+		s = s.withUnits(pairUnits(s, moves, "a king's move apart"))
+	}
+	if v.NonConsecutive {
+		masks := relation(s.size, func(x int, y int) bool {
+			return !consecutive(x, y)
+		})
+		names, pairs := pairUnits(s, sideBySide, "side by side")
+		constraints := []constraint{}
+		for i, pair := range pairs {
+			constraints = append(constraints, &pairRule{names[i], pair[0], pair[1], masks, "are consecutive"})
+		}
+		s = s.withConstraints(constraints)
+	}
+	if v.XMarks != nil || v.VMarks != nil {
+		s, err = markShape(s, []mark{
+			{"X mark", v.XMarks, relation(s.size, func(x int, y int) bool {
+				return x+y == 10
+			}), "do not add up to 10"},
+			{"V mark", v.VMarks, relation(s.size, func(x int, y int) bool {
+				return x+y == 5
+			}), "do not add up to 5"},
+		}, &mark{masks: relation(s.size, func(x int, y int) bool {
+			return x+y != 5 && x+y != 10
+		}), broken: "add up to 5 or 10 without a mark"})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.WhiteDots != nil || v.BlackDots != nil || v.AllDots {
+		var unmarked *mark
+		if v.AllDots {
+			unmarked = &mark{masks: relation(s.size, func(x int, y int) bool {
+				return !consecutive(x, y) && !double(x, y)
+			}), broken: "are consecutive or one twice the other without a dot"}
+		}
+		s, err = markShape(s, []mark{
+			{"White dot", v.WhiteDots, relation(s.size, consecutive), "are not consecutive"},
+			{"Black dot", v.BlackDots, relation(s.size, double), "are not one twice the other"},
+		}, unmarked)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.Cages != nil {
+		s, err = cageShape(s, v.Cages)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.Thermos != nil {
+		s, err = thermoShape(s, v.Thermos)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.Arrows != nil {
+		s, err = arrowShape(s, v.Arrows)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.Palindromes != nil {
+		s, err = palindromeShape(s, v.Palindromes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.SandwichRows != nil || v.SandwichColumns != nil {
+		s, err = sandwichShape(s, v.SandwichRows, v.SandwichColumns)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if v.Candidates != nil {
+		s, err = candidateShape(s, v.Candidates)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Returns the board like Board.String, with the cells of the thermometers
+// marked: each cell is followed by o for a bulb, = for the rest of a
+// thermometer or a space. The same as Board.String without thermometers.
+func (v Variant) Pretty(b Board) string {
+	return v.PrettyStyle(b, ASCII)
+}
+
+// Returns the board like Pretty, drawn in the style.
+func (v Variant) PrettyStyle(b Board, style Style) string {
+	s, ok := shapeOf(len(b))
+	if !ok {
+		s, _ = shapeOf(81)
+	}
+	if boxes, err := v.boxes(s); err == nil {
+		s = boxes
+	}
+	if v.Thermos == nil {
+		return b.pretty(s, nil, style)
+	}
+	marks := map[int]byte{}
+	for _, path := range v.Thermos {
+		for i, pos := range path {
+			if i == 0 {
+				marks[pos] = 'o'
+			} else if _, ok := marks[pos]; !ok {
+				marks[pos] = '='
+			}
+		}
+	}
+	return b.pretty(s, marks, style)
+}
+
+// Returns true if the variant has clues of a single puzzle, ie. cages,
+// rather than only rules for every puzzle.
+func (v Variant) hasClues() bool {
+	return v.Cages != nil || v.XMarks != nil || v.VMarks != nil || v.WhiteDots != nil ||
+		v.BlackDots != nil || v.Thermos != nil || v.Arrows != nil || v.Palindromes != nil ||
+		v.SandwichRows != nil || v.SandwichColumns != nil || v.Candidates != nil
+}
+
+// The move to the orthogonally adjacent cells, going down.
+var sideBySide = [][2]int{{0, 1}, {1, 0}}
+
+// A kind of mark between orthogonally adjacent cells, ie. the X of XV
+// sudoku, with the pairs of cells marked.
+type mark struct {
+	name  string
+	pairs [][2]int
+	// The values allowed next to each value, see relation.
+	masks []uint32
+	// Describes the values of cells breaking the rule, see pairRule.
+	broken string
+}
+
+// Returns the shape with the rules of the marks between adjacent cells, and
+// the unmarked rule for every other adjacent pair unless it is nil, or an
+// error if the marks are not valid for it.
+func markShape(s *shape, marks []mark, unmarked *mark) (*shape, error) {
+	marked := map[[2]int]*mark{}
+	for i := range marks {
+		for _, pair := range marks[i].pairs {
+			if pair[0] > pair[1] {
+				pair[0], pair[1] = pair[1], pair[0]
+			}
+			if _, ok := marked[pair]; ok {
+				error := fmt.Sprintf("Cells %d and %d are marked twice", pair[0], pair[1])
+				return nil, errors.New(error)
+			}
+			marked[pair] = &marks[i]
+		}
+	}
+
+	names, pairs := pairUnits(s, sideBySide, "side by side")
+	constraints := []constraint{}
+	for i, pair := range pairs {
+		key := [2]int{pair[0], pair[1]}
+		rule, ok := marked[key]
+		delete(marked, key)
+		if !ok {
+			if unmarked == nil {
+				continue
+			}
+			rule = unmarked
+		}
+		constraints = append(constraints, &pairRule{names[i], pair[0], pair[1], rule.masks, rule.broken})
+	}
+	for pair, rule := range marked {
+		error := fmt.Sprintf("%s between cells %d and %d, which are not side by side", rule.name, pair[0], pair[1])
+		return nil, errors.New(error)
+	}
+	return s.withConstraints(constraints), nil
+}
+
+// Returns true if x and y are consecutive.
+func consecutive(x int, y int) bool {
+	return x-y == 1 || y-x == 1
+}
+
+// Returns true if one of x and y is twice the other.
+func double(x int, y int) bool {
+	return x == 2*y || y == 2*x
+}
+
+// Returns the pairs of cells a move apart as units, named after the cells and
+// the move. Each move is a row and column offset, going down so every pair
+// is only found once.
+func pairUnits(s *shape, moves [][2]int, move string) ([]string, [][]int) {
+	names, units := []string{}, [][]int{}
+	for y := 0; y < s.size; y++ {
+		for x := 0; x < s.size; x++ {
+			for _, m := range moves {
+				toY, toX := y+m[0], x+m[1]
+				if toY >= s.size || toX < 0 || toX >= s.size {
+					continue
+				}
+				names = append(names, fmt.Sprintf("r%dc%d and r%dc%d, %s", y+1, x+1, toY+1, toX+1, move))
+				units = append(units, []int{s.cell(y, x), s.cell(toY, toX)})
+			}
+		}
+	}
+	return names, units
+}
+
+// Returns the shape with the thermometers as extra units, as their values
+// never repeat, and their rules as constraints, or an error if they are not
+// valid for it.
+func thermoShape(s *shape, thermos [][]int) (*shape, error) {
+	names, units, rules := []string{}, [][]int{}, []constraint{}
+	for i, path := range thermos {
+		name := fmt.Sprintf("thermometer %d", i+1)
+		if len(path) < 2 || len(path) > s.size {
+			error := fmt.Sprintf("Thermometer %d has %d cells, expected 2 to %d", i+1, len(path), s.size)
+			return nil, errors.New(error)
+		}
+		if err := checkPath(s, fmt.Sprintf("Thermometer %d", i+1), path); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		units = append(units, path)
+		rules = append(rules, &thermo{name: name, path: path, size: s.size})
+	}
+	return s.withUnits(names, units).withConstraints(rules), nil
+}
+
+// Returns the shape with the rules of the palindromes, each pair of cells the
+// same distance from the ends having the same value, or an error if they are
+// not valid for it.
+func palindromeShape(s *shape, palindromes [][]int) (*shape, error) {
+	same := relation(s.size, func(x int, y int) bool {
+		return x == y
+	})
+	rules := []constraint{}
+	for i, path := range palindromes {
+		if len(path) < 2 {
+			error := fmt.Sprintf("Palindrome %d has %d cells, expected at least 2", i+1, len(path))
+			return nil, errors.New(error)
+		}
+		if err := checkPath(s, fmt.Sprintf("Palindrome %d", i+1), path); err != nil {
+			return nil, err
+		}
+		for j := 0; j < len(path)/2; j++ {
+			a, b := path[j], path[len(path)-1-j]
+			name := fmt.Sprintf("r%dc%d and r%dc%d, mirrored on palindrome %d", a/s.size+1, a%s.size+1, b/s.size+1, b%s.size+1, i+1)
+			rules = append(rules, &pairRule{name, a, b, same, "are not the same"})
+		}
+	}
+	return s.withConstraints(rules), nil
+}
+
+// Returns an error if the path, named name, has a cell outside the board or
+// twice, or a cell not next to the previous one, side by side or diagonally.
+func checkPath(s *shape, name string, path []int) error {
+	seen := map[int]bool{}
+	for j, pos := range path {
+		if pos < 0 || pos >= s.size*s.size {
+			error := fmt.Sprintf("%s has a cell outside the board: %d", name, pos)
+			return errors.New(error)
+		}
+		if seen[pos] {
+			error := fmt.Sprintf("%s has cell %d twice", name, pos)
+			return errors.New(error)
+		}
+		seen[pos] = true
+		if j > 0 {
+			dy, dx := pos/s.size-path[j-1]/s.size, pos%s.size-path[j-1]%s.size
+			if dy < -1 || dy > 1 || dx < -1 || dx > 1 {
+				error := fmt.Sprintf("%s is broken between cells %d and %d", name, path[j-1], pos)
+				return errors.New(error)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the shape with the rules of the arrows, or an error if they are not
+// valid for it.
+func arrowShape(s *shape, arrows []Arrow) (*shape, error) {
+	rules := []constraint{}
+	for i, a := range arrows {
+		if len(a.Cells) < 1 || len(a.Cells) >= s.size {
+			error := fmt.Sprintf("Arrow %d has %d cells, expected 1 to %d", i+1, len(a.Cells), s.size-1)
+			return nil, errors.New(error)
+		}
+		path := append([]int{a.Circle}, a.Cells...)
+		if err := checkPath(s, fmt.Sprintf("Arrow %d", i+1), path); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &arrow{fmt.Sprintf("arrow %d", i+1), path, s.size})
+	}
+	return s.withConstraints(rules), nil
+}
+
+// Returns the shape with the candidates of each cell as constraints, or an
+// error if they are not valid for it.
+func candidateShape(s *shape, candidates [][]int) (*shape, error) {
+	if len(candidates) != s.size*s.size {
+		error := fmt.Sprintf("Candidates are given for %d cells, expected %d", len(candidates), s.size*s.size)
+		return nil, errors.New(error)
+	}
+	rules := []constraint{}
+	for pos, values := range candidates {
+		if values == nil {
+			continue
+		}
+		mask := uint32(0)
+		for _, val := range values {
+			if val < 1 || val > s.size {
+				error := fmt.Sprintf("Candidate %d of cell %d is not a value", val, pos)
+				return nil, errors.New(error)
+			}
+			mask |= 1 << uint(val)
+		}
+		rules = append(rules, &candidateSet{pos, mask})
+	}
+	return s.withConstraints(rules), nil
+}
+
+// Returns the shape with the rules of the sandwich sums of the rows and
+// columns, or an error if they are not valid for it.
+func sandwichShape(s *shape, rows []int, columns []int) (*shape, error) {
+	rules := []constraint{}
+	for kind, sums := range [][]int{rows, columns} {
+		name := []string{"row", "column"}[kind]
+		if sums == nil {
+			continue
+		}
+		if len(sums) != s.size {
+			error := fmt.Sprintf("Sandwich sums are given for %d %ss, expected %d", len(sums), name, s.size)
+			return nil, errors.New(error)
+		}
+		// The largest sum, of every value but 1 and the largest.
+		largest := s.size*(s.size+1)/2 - 1 - s.size
+		for i, sum := range sums {
+			if sum == -1 {
+				continue
+			}
+			var rule *sandwich
+			if sum >= 0 && sum <= largest {
+				line := s.unitCells[s.unit(0, kind)+i]
+				rule = newSandwich(fmt.Sprintf("%s %d", name, i+1), line, sum, s.size)
+			}
+			if rule == nil || rule.gaps == 0 {
+				error := fmt.Sprintf("Sandwich sum of %s %d is impossible: %d", name, i+1, sum)
+				return nil, errors.New(error)
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return s.withConstraints(rules), nil
+}
+
+// Returns the shape with the cages as extra units and their sums as
+// constraints, or an error if the cages are not valid for it.
+func cageShape(s *shape, cages []Cage) (*shape, error) {
+	names, units, sums := []string{}, [][]int{}, []constraint{}
+	for i, cage := range cages {
+		name := fmt.Sprintf("cage %d", i+1)
+		if len(cage.Cells) == 0 || len(cage.Cells) > s.size {
+			error := fmt.Sprintf("Cage %d has %d cells, expected 1 to %d", i+1, len(cage.Cells), s.size)
+			return nil, errors.New(error)
+		}
+		seen := map[int]bool{}
+		for _, pos := range cage.Cells {
+			if pos < 0 || pos >= s.size*s.size {
+				error := fmt.Sprintf("Cage %d has a cell outside the board: %d", i+1, pos)
+				return nil, errors.New(error)
+			}
+			if seen[pos] {
+				error := fmt.Sprintf("Cage %d has cell %d twice", i+1, pos)
+				return nil, errors.New(error)
+			}
+			seen[pos] = true
+		}
+		if cage.Sum < 0 || cage.Sum > s.size*(s.size+1)/2 {
+			error := fmt.Sprintf("Cage %d has an impossible sum: %d", i+1, cage.Sum)
+			return nil, errors.New(error)
+		}
+		names = append(names, name)
+		units = append(units, cage.Cells)
+		if cage.Sum > 0 {
+			sums = append(sums, &cageSum{name: name, cage: cage.Cells, sum: cage.Sum, size: s.size})
+		}
+	}
+	return s.withUnits(names, units).withConstraints(sums), nil
+}
+
+// Returns the classic shape s with the variant's box dimensions, or an error
+// if they do not fit it.
+func (v Variant) boxes(s *shape) (*shape, error) {
+	if v.BoxRows == 0 && v.BoxColumns == 0 || v.BoxRows == s.boxRows && v.BoxColumns == s.boxColumns {
+		return s, nil
+	}
+	if v.BoxRows < 1 || v.BoxColumns < 1 || v.BoxRows*v.BoxColumns != s.size {
+		error := fmt.Sprintf("Boxes of %dx%d do not fit a %dx%d board", v.BoxRows, v.BoxColumns, s.size, s.size)
+		return nil, errors.New(error)
+	}
+	return newShape(v.BoxRows, v.BoxColumns), nil
+}
+
+// Returns the shape with the regions as its boxes, or an error if the
+// regions are not valid for it.
+func regionShape(s *shape, regions []int) (*shape, error) {
+	cells := s.size * s.size
+	if len(regions) != cells {
+		error := fmt.Sprintf("Regions are given for %d cells, expected %d", len(regions), cells)
+		return nil, errors.New(error)
+	}
+	counts := make([]int, s.size)
+	for pos, region := range regions {
+		if region < 0 || region >= s.size {
+			error := fmt.Sprintf("Region is not between 0 and %d at position: %d", s.size-1, pos)
+			return nil, errors.New(error)
+		}
+		counts[region]++
+	}
+	for region, count := range counts {
+		if count != s.size {
+			error := fmt.Sprintf("Region %d has %d cells, expected %d", region+1, count, s.size)
+			return nil, errors.New(error)
+		}
+	}
+
+	// Every region must be reached from its first cell, going side by side.
+	seen := make([]bool, cells)
+	for pos, region := range regions {
+		if seen[pos] {
+			continue
+		}
+		reached := 0
+		queue := []int{pos}
+		seen[pos] = true
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+			reached++
+			y, x := cell/s.size, cell%s.size
+			for _, next := range [4][2]int{{y - 1, x}, {y + 1, x}, {y, x - 1}, {y, x + 1}} {
+				if next[0] < 0 || next[0] >= s.size || next[1] < 0 || next[1] >= s.size {
+					continue
+				}
+				neighbour := s.cell(next[0], next[1])
+				if !seen[neighbour] && regions[neighbour] == region {
+					seen[neighbour] = true
+					queue = append(queue, neighbour)
+				}
+			}
+		}
+		if reached != s.size {
+			error := fmt.Sprintf("Region %d is not connected", region+1)
+			return nil, errors.New(error)
+		}
+	}
+
+	result := &shape{size: s.size, boxRows: s.boxRows, boxColumns: s.boxColumns, boxName: "region"}
+	result.link(func(y int, x int) int {
+		return regions[y*s.size+x]
+	})
+	return result, nil
+}
+
+// Parses a region map, a character per cell where cells with the same
+// character are in the same region, ie. "111222333111222333...". Whitespace
+// is ignored, so the map can be written as rows. Regions are numbered from 0
+// in the order they first appear.
+func ParseRegions(text string) ([]int, error) {
+	numbers := map[rune]int{}
+	regions := []int{}
+	for _, c := range text {
+		if unicode.IsSpace(c) {
+			continue
+		}
+		if _, ok := numbers[c]; !ok {
+			numbers[c] = len(numbers)
+		}
+		regions = append(regions, numbers[c])
+	}
+	if len(regions) == 0 {
+		return nil, errors.New("No regions")
+	}
+	return regions, nil
+}